@@ -0,0 +1,224 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelic
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	"istio.io/istio/mixer/pkg/adapter"
+)
+
+// Account identifies where data is reported to: the API key, the ID and
+// the endpoints of the New Relic account. Empty endpoints refer to the
+// defaults of the clients.
+type Account struct {
+	APIKey      string
+	AccountID   string
+	MetricsHost string
+	SpansHost   string
+	LogsHost    string
+	EventsHost  string
+}
+
+// SecretConfig restricts where handlers read API keys from, so a handler
+// configuration cannot read other secrets of the adapter.
+type SecretConfig struct {
+	// Dir is the directory API key files are read from. Relative paths
+	// are relative to it and files outside of it are refused. No files
+	// are read if it is empty.
+	Dir string
+	// EnvPrefix is the prefix of the environment variables API keys are
+	// read from. No environment variables are read if it is empty.
+	EnvPrefix string
+}
+
+// ClientBuilder returns the clients reporting data to account. Metrics
+// and spans are sent with the Exporter and logs and events with the
+// ingest Harvester.
+type ClientBuilder func(account Account) (export.Exporter, *ingest.Harvester, error)
+
+// clients are the clients built for an Account.
+type clients struct {
+	exporter        export.Exporter
+	ingestHarvester *ingest.Harvester
+	// refs is the number of Handlers using the clients.
+	refs int
+}
+
+// close stops the clients and sends all data recorded with them. It
+// blocks until the data has been sent or ctx is done.
+func (c *clients) close(ctx context.Context) {
+	c.exporter.Close(ctx)
+	c.ingestHarvester.Close(ctx)
+}
+
+// regionHosts are the endpoints of each New Relic region. The events
+// endpoint is formatted with the account ID.
+var regionHosts = map[config.Params_Region]Account{
+	config.US: {
+		MetricsHost: "https://metric-api.newrelic.com/metric/v1",
+		SpansHost:   "https://trace-api.newrelic.com/trace/v1",
+		LogsHost:    "https://log-api.newrelic.com/log/v1",
+		EventsHost:  "https://insights-collector.newrelic.com/v1/accounts/%s/events",
+	},
+	config.EU: {
+		MetricsHost: "https://metric-api.eu.newrelic.com/metric/v1",
+		SpansHost:   "https://trace-api.eu.newrelic.com/trace/v1",
+		LogsHost:    "https://log-api.eu.newrelic.com/log/v1",
+		EventsHost:  "https://insights-collector.eu01.nr-data.net/v1/accounts/%s/events",
+	},
+}
+
+// ValidateAccountID returns an error if id is not a New Relic account ID.
+func ValidateAccountID(id string) error {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return fmt.Errorf("invalid account ID %q: must be a number", id)
+	}
+	return nil
+}
+
+// resolveAccount returns the Account data configured with cfg is reported
+// to. Anything not configured is taken from def, except the account ID of
+// an API key read from a secret. API keys are read as allowed by secrets,
+// and overriding an endpoint requires one so the API key of def is only
+// sent to New Relic.
+func resolveAccount(cfg *config.Params, def Account, secrets SecretConfig) (account Account, errs *adapter.ConfigErrors) {
+	account = def
+
+	secret := cfg.GetApiKeySecret()
+	if secret != nil {
+		key, err := readAPIKey(secret, secrets)
+		if err != nil {
+			errs = errs.Appendf("api_key_secret", "%v", err)
+		}
+		account.APIKey = key
+		account.AccountID = ""
+	}
+
+	if id := cfg.GetAccountId(); id != "" {
+		if err := ValidateAccountID(id); err != nil {
+			errs = errs.Appendf("account_id", "%v", err)
+		}
+		account.AccountID = id
+	}
+
+	if cfg.GetRegion() != config.REGION_UNSPECIFIED {
+		hosts, ok := regionHosts[cfg.GetRegion()]
+		if !ok {
+			errs = errs.Appendf("region", "unknown region: %v", cfg.GetRegion())
+		}
+		account.MetricsHost = hosts.MetricsHost
+		account.SpansHost = hosts.SpansHost
+		account.LogsHost = hosts.LogsHost
+		// Without an account ID events are not sent.
+		account.EventsHost = ""
+		if ok && account.AccountID != "" {
+			account.EventsHost = fmt.Sprintf(hosts.EventsHost, url.PathEscape(account.AccountID))
+		}
+	}
+
+	overrides := []struct {
+		field string
+		host  string
+		dest  *string
+	}{
+		{"metrics_host", cfg.GetMetricsHost(), &account.MetricsHost},
+		{"spans_host", cfg.GetSpansHost(), &account.SpansHost},
+		{"logs_host", cfg.GetLogsHost(), &account.LogsHost},
+		{"events_host", cfg.GetEventsHost(), &account.EventsHost},
+	}
+	for _, o := range overrides {
+		if o.host == "" {
+			continue
+		}
+		if secret == nil {
+			errs = errs.Appendf(o.field, "api_key_secret must be specified to override an endpoint")
+		}
+		*o.dest = o.host
+	}
+
+	return account, errs
+}
+
+// readAPIKey returns the API key referenced by secret if secrets allows
+// reading it.
+func readAPIKey(secret *config.Params_ApiKeySecret, secrets SecretConfig) (string, error) {
+	env, file := secret.GetEnv(), secret.GetFile()
+	switch {
+	case env != "" && file != "":
+		return "", fmt.Errorf("only one of env or file can be specified")
+	case env != "":
+		if secrets.EnvPrefix == "" {
+			return "", fmt.Errorf("reading API keys from environment variables is disabled")
+		}
+		if !strings.HasPrefix(env, secrets.EnvPrefix) {
+			return "", fmt.Errorf("environment variable %q does not start with %q", env, secrets.EnvPrefix)
+		}
+		key, ok := os.LookupEnv(env)
+		if !ok || key == "" {
+			return "", fmt.Errorf("environment variable %q is not set", env)
+		}
+		return key, nil
+	case file != "":
+		path, err := secretPath(secrets.Dir, file)
+		if err != nil {
+			return "", err
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read API key: %v", err)
+		}
+		key := strings.TrimSpace(string(b))
+		if key == "" {
+			return "", fmt.Errorf("file %q is empty", file)
+		}
+		return key, nil
+	default:
+		return "", fmt.Errorf("one of env or file must be specified")
+	}
+}
+
+// secretPath returns the path of file relative to dir, with symbolic
+// links resolved, or an error if it is not in dir.
+func secretPath(dir, file string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("reading API keys from files is disabled")
+	}
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read API key: %v", err)
+	}
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return "", fmt.Errorf("failed to read API key: %v", err)
+	}
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file %q is not in %q", file, dir)
+	}
+	return path, nil
+}
@@ -0,0 +1,304 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelic
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+)
+
+func TestResolveAccount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "api-key")
+	if err := ioutil.WriteFile(keyFile, []byte("file-key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := ioutil.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	outside, err := ioutil.TempFile("", "api-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(outside.Name())
+	outside.WriteString("outside-key")
+	outside.Close()
+	linkFile := filepath.Join(dir, "link")
+	if err := os.Symlink(outside.Name(), linkFile); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("TEST_NEW_RELIC_API_KEY", "env-key")
+	defer os.Unsetenv("TEST_NEW_RELIC_API_KEY")
+	os.Setenv("TEST_OTHER_SECRET", "other")
+	defer os.Unsetenv("TEST_OTHER_SECRET")
+
+	secrets := SecretConfig{Dir: dir, EnvPrefix: "TEST_NEW_RELIC_"}
+
+	def := Account{APIKey: "default-key", AccountID: "12345", MetricsHost: "http://localhost/metrics"}
+	euEvents := "https://insights-collector.eu01.nr-data.net/v1/accounts/12345/events"
+
+	testCases := []struct {
+		name           string
+		params         *config.Params
+		expected       Account
+		expectedErrMsg string
+	}{
+		{
+			"default",
+			&config.Params{},
+			def,
+			"",
+		},
+		{
+			"env secret",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{Env: "TEST_NEW_RELIC_API_KEY"}},
+			Account{APIKey: "env-key", MetricsHost: def.MetricsHost},
+			"",
+		},
+		{
+			"file secret",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{File: keyFile}},
+			Account{APIKey: "file-key", MetricsHost: def.MetricsHost},
+			"",
+		},
+		{
+			"relative file secret",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{File: "api-key"}},
+			Account{APIKey: "file-key", MetricsHost: def.MetricsHost},
+			"",
+		},
+		{
+			"secret and account ID",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{File: keyFile}, AccountId: "67890"},
+			Account{APIKey: "file-key", AccountID: "67890", MetricsHost: def.MetricsHost},
+			"",
+		},
+		{
+			"EU region",
+			&config.Params{Region: config.EU},
+			Account{APIKey: "default-key", AccountID: "12345", MetricsHost: regionHosts[config.EU].MetricsHost, SpansHost: regionHosts[config.EU].SpansHost,
+				LogsHost: regionHosts[config.EU].LogsHost, EventsHost: euEvents},
+			"",
+		},
+		{
+			"EU region without account ID",
+			&config.Params{Region: config.EU, ApiKeySecret: &config.Params_ApiKeySecret{File: keyFile}},
+			Account{APIKey: "file-key", MetricsHost: regionHosts[config.EU].MetricsHost, SpansHost: regionHosts[config.EU].SpansHost,
+				LogsHost: regionHosts[config.EU].LogsHost},
+			"",
+		},
+		{
+			"host overrides region",
+			&config.Params{Region: config.EU, SpansHost: "http://localhost/spans", ApiKeySecret: &config.Params_ApiKeySecret{File: keyFile}},
+			Account{APIKey: "file-key", MetricsHost: regionHosts[config.EU].MetricsHost, SpansHost: "http://localhost/spans",
+				LogsHost: regionHosts[config.EU].LogsHost},
+			"",
+		},
+		{
+			"host without secret",
+			&config.Params{SpansHost: "http://localhost/spans"},
+			Account{},
+			"api_key_secret must be specified",
+		},
+		{
+			"file outside dir",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{File: outside.Name()}},
+			Account{},
+			"is not in",
+		},
+		{
+			"link outside dir",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{File: linkFile}},
+			Account{},
+			"is not in",
+		},
+		{
+			"env without prefix",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{Env: "TEST_OTHER_SECRET"}},
+			Account{},
+			"does not start with",
+		},
+		{
+			"invalid account ID",
+			&config.Params{AccountId: "acme"},
+			Account{},
+			"must be a number",
+		},
+		{
+			"unset env",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{Env: "TEST_NEW_RELIC_UNSET"}},
+			Account{},
+			"is not set",
+		},
+		{
+			"empty file",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{File: emptyFile}},
+			Account{},
+			"is empty",
+		},
+		{
+			"missing file",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{File: filepath.Join(dir, "missing")}},
+			Account{},
+			"failed to read API key",
+		},
+		{
+			"env and file",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{Env: "TEST_NEW_RELIC_API_KEY", File: keyFile}},
+			Account{},
+			"only one of env or file",
+		},
+		{
+			"empty secret",
+			&config.Params{ApiKeySecret: &config.Params_ApiKeySecret{}},
+			Account{},
+			"one of env or file must be specified",
+		},
+		{
+			"unknown region",
+			&config.Params{Region: config.Params_Region(42)},
+			Account{},
+			"unknown region",
+		},
+	}
+
+	for _, tc := range testCases {
+		account, err := resolveAccount(tc.params, def, secrets)
+		if tc.expectedErrMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg) {
+				t.Errorf("%s: Expected error to contain '%s', got %v", tc.name, tc.expectedErrMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Did not expect error, got %v", tc.name, err)
+			continue
+		}
+		if account != tc.expected {
+			t.Errorf("%s: Expected account %+v, got %+v", tc.name, tc.expected, account)
+		}
+	}
+
+	// Secrets are not read unless allowed.
+	for _, secret := range []*config.Params_ApiKeySecret{{File: keyFile}, {Env: "TEST_NEW_RELIC_API_KEY"}} {
+		if _, err := readAPIKey(secret, SecretConfig{}); err == nil || !strings.Contains(err.Error(), "disabled") {
+			t.Errorf("%v: Expected error to contain 'disabled', got %v", secret, err)
+		}
+	}
+}
+
+func TestGetHandlerReusesClients(t *testing.T) {
+	built := make(map[Account]int)
+	build := func(a Account) (export.Exporter, *ingest.Harvester, error) {
+		built[a]++
+		return &export.Recorder{}, nil, nil
+	}
+
+	s, err := NewServer(":0", Account{APIKey: "default-key"}, SecretConfig{}, build, 0, HandlerCacheConfig{}, QueueConfig{})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer s.Close(context.Background())
+
+	configs := []*config.Params{
+		{Namespace: "a"},
+		{Namespace: "b", Region: config.EU},
+		{Namespace: "c"},
+		{Namespace: "d", Region: config.EU},
+	}
+	for _, cfg := range configs {
+		rawcfg, err := cfg.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := s.getHandler(rawcfg)
+		if err != nil {
+			t.Fatalf("failed to get handler: %v", err)
+		}
+		h.release()
+	}
+
+	if len(built) != 2 {
+		t.Errorf("expected clients for 2 accounts, got %v", built)
+	}
+	for a, n := range built {
+		if n != 1 {
+			t.Errorf("expected clients for %+v to be built once, got %d", a, n)
+		}
+	}
+}
+
+func TestReleaseClients(t *testing.T) {
+	exporters := make(map[Account][]*export.Recorder)
+	build := func(a Account) (export.Exporter, *ingest.Harvester, error) {
+		r := &export.Recorder{}
+		exporters[a] = append(exporters[a], r)
+		return r, nil, nil
+	}
+
+	def := Account{APIKey: "default-key"}
+	s, err := NewServer(":0", def, SecretConfig{}, build, 0, HandlerCacheConfig{Size: 1}, QueueConfig{})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer s.Close(context.Background())
+
+	getHandler := func(cfg *config.Params) {
+		rawcfg, err := cfg.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := s.getHandler(rawcfg)
+		if err != nil {
+			t.Fatalf("failed to get handler: %v", err)
+		}
+		h.release()
+		s.releasing.Wait()
+	}
+
+	eu, errs := resolveAccount(&config.Params{Region: config.EU}, def, SecretConfig{})
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	getHandler(&config.Params{Namespace: "a", Region: config.EU})
+	// Evicting the only handler reporting to the EU account closes its
+	// clients.
+	getHandler(&config.Params{Namespace: "b"})
+	if n := exporters[eu][0].Flushes(); n != 1 {
+		t.Errorf("expected the EU clients to be closed, got %d flushes", n)
+	}
+	// The clients of the default account are kept for the self metrics.
+	getHandler(&config.Params{Namespace: "c", Region: config.EU})
+	if n := exporters[def][0].Flushes(); n != 0 {
+		t.Errorf("expected the default clients to be kept, got %d flushes", n)
+	}
+	if n := len(exporters[eu]); n != 2 {
+		t.Errorf("expected the EU clients to be rebuilt, got %d builds", n)
+	}
+}
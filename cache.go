@@ -0,0 +1,257 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelic
+
+import (
+	"bytes"
+	"hash/fnv"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/log"
+)
+
+// HandlerCacheConfig configures the cache of the Handlers built for each
+// distinct Mixer handler configuration.
+type HandlerCacheConfig struct {
+	// Size is the maximum number of Handlers cached. The least recently
+	// used Handler is evicted to make room for a new one. Zero or less
+	// means the number of Handlers is not limited.
+	Size int
+	// IdleTimeout is how long a Handler stays cached without being used.
+	// Zero means Handlers are never evicted for being idle.
+	IdleTimeout time.Duration
+}
+
+// HandlerCacheStats are the counts of the handler cache since the Server
+// was created.
+type HandlerCacheStats struct {
+	// Hits is the number of requests served by a cached Handler.
+	Hits uint64
+	// Misses is the number of Handlers built for a configuration not
+	// in the cache.
+	Misses uint64
+	// Rebuilds is the number of misses for configurations whose Handler
+	// had previously been evicted. Only the configurations of the last
+	// 1024 evicted Handlers are remembered.
+	Rebuilds uint64
+	// Evictions is the number of Handlers evicted from the cache.
+	Evictions uint64
+	// Size is the number of Handlers currently cached.
+	Size int
+}
+
+// cachedHandler is a Handler along with the configuration it was built
+// for.
+type cachedHandler struct {
+	rawcfg  []byte
+	handler *Handler
+	// lastUsed is the unix time in nanoseconds the handler was last
+	// returned from the cache. It is accessed atomically.
+	lastUsed int64
+}
+
+// maxEvicted is the number of evicted configurations remembered to count
+// rebuilds.
+const maxEvicted = 1024
+
+// handlerCache holds the Handlers built for each configuration keyed by a
+// hash of the configuration. Evicted Handlers are closed once the requests
+// holding a reference to them are done.
+type handlerCache struct {
+	cfg HandlerCacheConfig
+
+	lock    sync.RWMutex
+	entries map[uint64]*cachedHandler
+	// evicted maps the keys of evicted configurations to their eviction
+	// count.
+	evicted map[uint64]uint64
+
+	// hits, misses, rebuilds and evictions are accessed atomically.
+	hits, misses, rebuilds, evictions uint64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newHandlerCache(cfg HandlerCacheConfig) *handlerCache {
+	c := &handlerCache{
+		cfg:     cfg,
+		entries: make(map[uint64]*cachedHandler),
+		evicted: make(map[uint64]uint64),
+	}
+	if cfg.IdleTimeout > 0 {
+		c.start(cfg.IdleTimeout / 2)
+	}
+	return c
+}
+
+// hashConfig returns the key of the raw handler configuration rawcfg.
+func hashConfig(rawcfg []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(rawcfg)
+	return h.Sum64()
+}
+
+// get returns the cached Handler built for rawcfg, if any, with a
+// reference taken the caller must release.
+func (c *handlerCache) get(key uint64, rawcfg []byte, now time.Time) (*Handler, bool) {
+	c.lock.RLock()
+	e, ok := c.entries[key]
+	ok = ok && bytes.Equal(e.rawcfg, rawcfg)
+	if ok {
+		// Taken under the lock so the Handler cannot be evicted and
+		// closed first.
+		e.handler.acquire()
+	}
+	c.lock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	atomic.StoreInt64(&e.lastUsed, now.UnixNano())
+	atomic.AddUint64(&c.hits, 1)
+	return e.handler, true
+}
+
+// add caches h as the Handler built for rawcfg, evicting the least
+// recently used Handler if the cache is full. It returns whether a Handler
+// for rawcfg had been cached before.
+func (c *handlerCache) add(key uint64, rawcfg []byte, h *Handler, now time.Time) (rebuild bool) {
+	var retiring []*Handler
+
+	c.lock.Lock()
+	if e, ok := c.entries[key]; ok {
+		// A different configuration with the same hash.
+		retiring = append(retiring, e.handler)
+		delete(c.entries, key)
+	} else if c.cfg.Size > 0 && len(c.entries) >= c.cfg.Size {
+		lru, lruKey := (*cachedHandler)(nil), uint64(0)
+		for k, e := range c.entries {
+			if lru == nil || atomic.LoadInt64(&e.lastUsed) < atomic.LoadInt64(&lru.lastUsed) {
+				lru, lruKey = e, k
+			}
+		}
+		retiring = append(retiring, c.evict(lruKey, lru))
+	}
+
+	atomic.AddUint64(&c.misses, 1)
+	if _, rebuild = c.evicted[key]; rebuild {
+		atomic.AddUint64(&c.rebuilds, 1)
+		delete(c.evicted, key)
+	}
+	c.entries[key] = &cachedHandler{rawcfg: rawcfg, handler: h, lastUsed: now.UnixNano()}
+	c.lock.Unlock()
+
+	for _, h := range retiring {
+		h.retire()
+	}
+	return rebuild
+}
+
+// evict removes the entry e cached with key. The caller must hold the
+// lock and retire the returned Handler.
+func (c *handlerCache) evict(key uint64, e *cachedHandler) *Handler {
+	delete(c.entries, key)
+	c.evicted[key] = atomic.AddUint64(&c.evictions, 1)
+	if len(c.evicted) > maxEvicted {
+		// Forget the configuration evicted longest ago.
+		oldestKey, oldest := uint64(0), uint64(math.MaxUint64)
+		for k, n := range c.evicted {
+			if n < oldest {
+				oldestKey, oldest = k, n
+			}
+		}
+		delete(c.evicted, oldestKey)
+	}
+	return e.handler
+}
+
+// evictIdle evicts the Handlers not used since the idle timeout before
+// now and closes them once they are no longer in use.
+func (c *handlerCache) evictIdle(now time.Time) {
+	cutoff := now.Add(-c.cfg.IdleTimeout).UnixNano()
+	var retiring []*Handler
+
+	c.lock.Lock()
+	for k, e := range c.entries {
+		if atomic.LoadInt64(&e.lastUsed) <= cutoff {
+			retiring = append(retiring, c.evict(k, e))
+		}
+	}
+	c.lock.Unlock()
+
+	if len(retiring) > 0 {
+		log.Debugf("evicted %d idle handlers", len(retiring))
+	}
+	for _, h := range retiring {
+		h.retire()
+	}
+}
+
+// start evicts idle Handlers every period until the cache is closed.
+func (c *handlerCache) start(period time.Duration) {
+	c.done = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C:
+				c.evictIdle(t)
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
+
+// stats returns the counts of the cache.
+func (c *handlerCache) stats() HandlerCacheStats {
+	c.lock.RLock()
+	size := len(c.entries)
+	c.lock.RUnlock()
+
+	return HandlerCacheStats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Rebuilds:  atomic.LoadUint64(&c.rebuilds),
+		Evictions: atomic.LoadUint64(&c.evictions),
+		Size:      size,
+	}
+}
+
+// close stops evicting idle Handlers and closes all cached Handlers once
+// they are no longer in use.
+func (c *handlerCache) close() {
+	if c.done != nil {
+		close(c.done)
+		c.wg.Wait()
+		c.done = nil
+	}
+
+	c.lock.Lock()
+	entries := c.entries
+	c.entries = make(map[uint64]*cachedHandler)
+	c.lock.Unlock()
+
+	for _, e := range entries {
+		e.handler.retire()
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelic
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/metric"
+)
+
+func newTestServer(t *testing.T, cache HandlerCacheConfig) *Server {
+	build := func(Account) (export.Exporter, *ingest.Harvester, error) {
+		return &export.Recorder{}, nil, nil
+	}
+	s, err := NewServer(":0", Account{}, SecretConfig{}, build, 0, cache, QueueConfig{})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	return s
+}
+
+func marshalParams(t *testing.T, namespace string) []byte {
+	rawcfg, err := (&config.Params{Namespace: namespace}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rawcfg
+}
+
+func TestHandlerCacheMultipleConfigs(t *testing.T) {
+	s := newTestServer(t, HandlerCacheConfig{})
+	defer s.Close(context.Background())
+
+	a, b := marshalParams(t, "a"), marshalParams(t, "b")
+	handlers := make(map[string]*Handler)
+	for i := 0; i < 3; i++ {
+		for name, rawcfg := range map[string][]byte{"a": a, "b": b} {
+			h, err := s.getHandler(rawcfg)
+			if err != nil {
+				t.Fatalf("failed to get handler: %v", err)
+			}
+			h.release()
+			if prev, ok := handlers[name]; ok && prev != h {
+				t.Errorf("config %s: expected cached handler to be reused", name)
+			}
+			handlers[name] = h
+		}
+	}
+
+	// The nil config built by NewServer, a and b.
+	expected := HandlerCacheStats{Hits: 4, Misses: 3, Size: 3}
+	if stats := s.HandlerCacheStats(); stats != expected {
+		t.Errorf("expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestHandlerCacheSizeEviction(t *testing.T) {
+	s := newTestServer(t, HandlerCacheConfig{Size: 2})
+	defer s.Close(context.Background())
+
+	a, b := marshalParams(t, "a"), marshalParams(t, "b")
+	for _, rawcfg := range [][]byte{a, b, nil, a} {
+		h, err := s.getHandler(rawcfg)
+		if err != nil {
+			t.Fatalf("failed to get handler: %v", err)
+		}
+		h.release()
+	}
+
+	// Each build evicts the least recently used of the other two configs.
+	expected := HandlerCacheStats{Misses: 5, Rebuilds: 2, Evictions: 3, Size: 2}
+	if stats := s.HandlerCacheStats(); stats != expected {
+		t.Errorf("expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestHandlerCacheIdleEviction(t *testing.T) {
+	c := newHandlerCache(HandlerCacheConfig{IdleTimeout: time.Minute})
+	defer c.close()
+
+	s := newTestServer(t, HandlerCacheConfig{})
+	defer s.Close(context.Background())
+	h, err := s.getHandler(nil)
+	if err != nil {
+		t.Fatalf("failed to get handler: %v", err)
+	}
+	h.release()
+
+	now := time.Now()
+	a, b := []byte("a"), []byte("b")
+	c.add(hashConfig(a), a, h, now)
+	c.add(hashConfig(b), b, h, now)
+	if _, ok := c.get(hashConfig(b), b, now.Add(time.Minute)); !ok {
+		t.Fatal("expected b to be cached")
+	}
+
+	c.evictIdle(now.Add(90 * time.Second))
+	if _, ok := c.get(hashConfig(a), a, now); ok {
+		t.Error("expected idle a to be evicted")
+	}
+	if _, ok := c.get(hashConfig(b), b, now); !ok {
+		t.Error("expected recently used b to stay cached")
+	}
+	if stats := c.stats(); stats.Evictions != 1 || stats.Size != 1 {
+		t.Errorf("expected 1 eviction and 1 cached handler, got %+v", stats)
+	}
+}
+
+func TestHandlerCacheEvictionWaitsForRequests(t *testing.T) {
+	recorder := &export.Recorder{}
+	build := func(Account) (export.Exporter, *ingest.Harvester, error) {
+		return recorder, nil, nil
+	}
+	s, err := NewServer(":0", Account{}, SecretConfig{}, build, time.Hour, HandlerCacheConfig{Size: 1}, QueueConfig{})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer s.Close(context.Background())
+
+	rawcfg, err := (&config.Params{
+		Metrics: map[string]*config.Params_MetricInfo{
+			"requests.instance.istio-system": {Name: "requests", Type: config.COUNT},
+		},
+	}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := s.getHandler(rawcfg)
+	if err != nil {
+		t.Fatalf("failed to get handler: %v", err)
+	}
+	// Evict h while a request holds it.
+	other, err := s.getHandler(marshalParams(t, "other"))
+	if err != nil {
+		t.Fatalf("failed to get handler: %v", err)
+	}
+	other.release()
+
+	err = h.HandleMetric(context.Background(), []*metric.InstanceMsg{{
+		Name:       "requests.instance.istio-system",
+		Value:      &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 1}},
+		Dimensions: map[string]*policy.Value{},
+	}})
+	if err != nil {
+		t.Fatalf("HandleMetric errored: %v", err)
+	}
+	if n := len(recorder.Metrics()); n != 0 {
+		t.Fatalf("expected no metrics recorded before the handler is released, got %d", n)
+	}
+	h.release()
+	if n := len(recorder.Metrics()); n != 1 {
+		t.Errorf("expected the metric recorded once the evicted handler is released, got %d", n)
+	}
+}
+
+func TestHandlerCacheEvictedBound(t *testing.T) {
+	c := newHandlerCache(HandlerCacheConfig{Size: 1})
+	defer c.close()
+
+	s := newTestServer(t, HandlerCacheConfig{})
+	defer s.Close(context.Background())
+	h, err := s.getHandler(nil)
+	if err != nil {
+		t.Fatalf("failed to get handler: %v", err)
+	}
+	h.release()
+
+	now := time.Now()
+	for i := 0; i < maxEvicted+10; i++ {
+		rawcfg := []byte(strconv.Itoa(i))
+		c.add(hashConfig(rawcfg), rawcfg, h, now)
+	}
+	if n := len(c.evicted); n != maxEvicted {
+		t.Errorf("expected %d evicted configurations remembered, got %d", maxEvicted, n)
+	}
+	// The configuration evicted first has been forgotten.
+	first := []byte("0")
+	if c.add(hashConfig(first), first, h, now) {
+		t.Error("expected the first evicted configuration not to count as a rebuild")
+	}
+	last := []byte(strconv.Itoa(maxEvicted + 8))
+	if !c.add(hashConfig(last), last, h, now) {
+		t.Error("expected a recently evicted configuration to count as a rebuild")
+	}
+}
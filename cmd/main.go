@@ -16,16 +16,25 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
 
 	newrelic "github.com/newrelic/newrelic-istio-adapter"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/health"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
 	"github.com/newrelic/newrelic-istio-adapter/log"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+	"github.com/newrelic/newrelic-istio-adapter/spool"
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -41,12 +50,31 @@ var (
 	clusterNamePtr   = kingpin.Flag("cluster-name", "Name of cluster where metrics come from").OverrideDefaultFromEnvar("NEW_RELIC_CLUSTER_NAME").String()
 	logLevelPtr      = kingpin.Flag("log-level", "set logging level").OverrideDefaultFromEnvar("NEW_RELIC_LOG_LEVEL").Default("error").Enum(log.Levels()...)
 	harvestPeriodPtr = kingpin.Flag("harvest-period", "rate data is reported to New Relic").Default("5s").OverrideDefaultFromEnvar("NEW_RELIC_HARVEST_PERIOD").Duration()
+	accountIDPtr     = kingpin.Flag("account-id", "ID of the New Relic account the API key belongs to, required to report edges").OverrideDefaultFromEnvar("NEW_RELIC_ACCOUNT_ID").String()
+	secretsDirPtr    = kingpin.Flag("api-key-secrets-dir", "Directory handlers may read API key files from with api_key_secret").OverrideDefaultFromEnvar("NEW_RELIC_API_KEY_SECRETS_DIR").String()
+	secretsEnvPtr    = kingpin.Flag("api-key-env-prefix", "Prefix of the environment variables handlers may read API keys from with api_key_secret, empty to disallow").Default("NEW_RELIC_API_KEY").OverrideDefaultFromEnvar("NEW_RELIC_API_KEY_ENV_PREFIX").String()
 	metricsHostPtr   = kingpin.Flag("metrics-host", "Endpoint to send metrics (used for debugging)").OverrideDefaultFromEnvar("NEW_RELIC_METRICS_HOST").String()
 	spansHostPtr     = kingpin.Flag("spans-host", "Endpoint to send spans (used for debugging)").OverrideDefaultFromEnvar("NEW_RELIC_SPANS_HOST").String()
+	logsHostPtr      = kingpin.Flag("logs-host", "Endpoint to send logs (used for debugging)").OverrideDefaultFromEnvar("NEW_RELIC_LOGS_HOST").String()
+	eventsHostPtr    = kingpin.Flag("events-host", "Endpoint to send events (used for debugging)").OverrideDefaultFromEnvar("NEW_RELIC_EVENTS_HOST").String()
 	mtlsCertPtr      = kingpin.Flag("cert", "mTLS certificate for gRPC server").OverrideDefaultFromEnvar("NEW_RELIC_MTLS_CERT").ExistingFile()
 	mtlsKeyPtr       = kingpin.Flag("key", "mTLS key for gRPC server").OverrideDefaultFromEnvar("NEW_RELIC_MTLS_KEY").ExistingFile()
 	mtlsCAPtr        = kingpin.Flag("ca", "mTLS CA certificate for gRPC server").OverrideDefaultFromEnvar("NEW_RELIC_MTLS_CA").ExistingFile()
-	apiKeyPtr        = kingpin.Arg("api-key", "New Relic API key").Envar("NEW_RELIC_API_KEY").Required().String()
+	dryRunPtr        = kingpin.Flag("dry-run", "Write metrics and spans to stdout as newline delimited JSON instead of sending data to New Relic").OverrideDefaultFromEnvar("NEW_RELIC_DRY_RUN").Bool()
+	outputPtr        = kingpin.Flag("output", "Write metrics and spans to a file:///path URL as newline delimited JSON instead of sending data to New Relic").OverrideDefaultFromEnvar("NEW_RELIC_OUTPUT").String()
+	cacheSizePtr     = kingpin.Flag("handler-cache-size", "Maximum number of handler configurations cached, 0 for no limit").Default("16").OverrideDefaultFromEnvar("NEW_RELIC_HANDLER_CACHE_SIZE").Int()
+	idleTimeoutPtr   = kingpin.Flag("handler-idle-timeout", "Time an unused handler configuration stays cached, 0 to never evict idle handlers").Default("30m").OverrideDefaultFromEnvar("NEW_RELIC_HANDLER_IDLE_TIMEOUT").Duration()
+	shutdownPtr      = kingpin.Flag("shutdown-timeout", "Time allowed to drain in-flight requests and send recorded data on shutdown").Default("10s").OverrideDefaultFromEnvar("NEW_RELIC_SHUTDOWN_TIMEOUT").Duration()
+	metricsAddrPtr   = kingpin.Flag("metrics-addr", "Address to serve Prometheus metrics about the adapter on at /metrics, e.g. :9090").OverrideDefaultFromEnvar("NEW_RELIC_METRICS_ADDR").String()
+	healthAddrPtr    = kingpin.Flag("health-addr", "Address to serve the HTTP liveness (/healthz) and readiness (/readyz) endpoints on, e.g. :8080").OverrideDefaultFromEnvar("NEW_RELIC_HEALTH_ADDR").String()
+	failuresPtr      = kingpin.Flag("readiness-failure-threshold", "Consecutive failed posts to a New Relic endpoint after which the adapter is not ready, 0 to disable").Default("5").OverrideDefaultFromEnvar("NEW_RELIC_READINESS_FAILURE_THRESHOLD").Int()
+	queueCapPtr      = kingpin.Flag("queue-capacity", "Maximum number of Mixer requests queued to be handled, 0 to handle requests as they are received").OverrideDefaultFromEnvar("NEW_RELIC_QUEUE_CAPACITY").Int()
+	queueWorkersPtr  = kingpin.Flag("queue-workers", "Number of queued Mixer requests handled concurrently").Default("4").OverrideDefaultFromEnvar("NEW_RELIC_QUEUE_WORKERS").Int()
+	queuePolicyPtr   = kingpin.Flag("queue-full-policy", "What to do with Mixer requests received while the queue is full").Default(string(newrelic.Reject)).OverrideDefaultFromEnvar("NEW_RELIC_QUEUE_FULL_POLICY").Enum(newrelic.QueuePolicies()...)
+	spoolDirPtr      = kingpin.Flag("spool-dir", "Directory to spool payloads that fail to post to New Relic to, replaying them once New Relic is reachable").OverrideDefaultFromEnvar("NEW_RELIC_SPOOL_DIR").String()
+	spoolMaxSizePtr  = kingpin.Flag("spool-max-size", "Maximum size of the spooled payloads, the oldest are dropped beyond it, 0 for no limit").Default("256MB").OverrideDefaultFromEnvar("NEW_RELIC_SPOOL_MAX_SIZE").Bytes()
+	spoolMaxAgePtr   = kingpin.Flag("spool-max-age", "Time a payload is kept in the spool, 0 for no limit").Default("1h").OverrideDefaultFromEnvar("NEW_RELIC_SPOOL_MAX_AGE").Duration()
+	apiKeyPtr        = kingpin.Arg("api-key", "New Relic API key, required unless --dry-run or --output is set").Envar("NEW_RELIC_API_KEY").String()
 )
 
 func getServerTLSOption(cert, key, ca string) (grpc.ServerOption, error) {
@@ -75,9 +103,126 @@ func getServerTLSOption(cert, key, ca string) (grpc.ServerOption, error) {
 	return grpc.Creds(credentials.NewTLS(tlsConfig)), nil
 }
 
+// openOutput returns the writer for the dry run output URL. An empty
+// output refers to stdout.
+func openOutput(output string) (io.Writer, error) {
+	if output == "" {
+		return os.Stdout, nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output %q: %v", output, err)
+	}
+	if u.Scheme != "file" || u.Path == "" {
+		return nil, fmt.Errorf("invalid output %q: must be a file:///path URL", output)
+	}
+
+	f, err := os.OpenFile(u.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output: %v", err)
+	}
+	return f, nil
+}
+
+// sharedExporter is an Exporter shared by the clients of every account. It
+// is only flushed when the clients of an account are closed.
+type sharedExporter struct {
+	export.Exporter
+}
+
+// Close sends all recorded data.
+func (e sharedExporter) Close(ctx context.Context) {
+	e.Flush(ctx)
+}
+
+// buildClients returns the clients reporting data to account with
+// transport.
+func buildClients(account newrelic.Account, commonAttrs map[string]interface{}, transport http.RoundTripper) (export.Exporter, *ingest.Harvester, error) {
+	h, err := telemetry.NewHarvester(
+		telemetry.ConfigAPIKey(account.APIKey),
+		telemetry.ConfigCommonAttributes(commonAttrs),
+		// The exporter harvests so it can be stopped with the clients.
+		telemetry.ConfigHarvestPeriod(0),
+		log.HarvesterConfigFunc(),
+		func(cfg *telemetry.Config) {
+			cfg.MetricsURLOverride = account.MetricsHost
+			cfg.SpansURLOverride = account.SpansHost
+			cfg.Client.Transport = transport
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create harvester: %v", err)
+	}
+
+	ih, err := ingest.NewHarvester(
+		ingest.ConfigAPIKey(account.APIKey),
+		ingest.ConfigCommonAttributes(commonAttrs),
+		ingest.ConfigHarvestPeriod(*harvestPeriodPtr),
+		log.IngestConfigFunc(),
+		func(cfg *ingest.Config) {
+			cfg.AccountID = account.AccountID
+			cfg.LogsURLOverride = account.LogsHost
+			cfg.EventsURLOverride = account.EventsHost
+			cfg.Client.Transport = transport
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create ingest harvester: %v", err)
+	}
+
+	return export.NewHarvester(h, *harvestPeriodPtr), ih, nil
+}
+
+// serveHTTP serves the HTTP endpoints of the adapter. The Prometheus
+// metrics and health endpoints share a server if their addresses are the
+// same.
+func serveHTTP(checker *health.Checker) {
+	muxes := make(map[string]*http.ServeMux)
+	mux := func(addr string) *http.ServeMux {
+		if _, ok := muxes[addr]; !ok {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+	if *metricsAddrPtr != "" {
+		mux(*metricsAddrPtr).Handle("/metrics", selfmetric.PrometheusHandler())
+	}
+	if *healthAddrPtr != "" {
+		checker.Register(mux(*healthAddrPtr))
+	}
+
+	for addr, m := range muxes {
+		go func(addr string, m *http.ServeMux) {
+			log.Infof("serving HTTP on %q", addr)
+			if err := http.ListenAndServe(addr, m); err != nil {
+				log.Errorf("failed to serve HTTP: %v\n", err)
+			}
+		}(addr, m)
+	}
+}
+
 func main() {
 	kingpin.Version(Version)
 	kingpin.Parse()
+	selfmetric.SetVersion(Version)
+
+	dryRun := *dryRunPtr || *outputPtr != ""
+	if !dryRun && *apiKeyPtr == "" {
+		kingpin.Fatalf("required argument 'api-key' not provided, try --help")
+	}
+	if *accountIDPtr != "" {
+		if err := newrelic.ValidateAccountID(*accountIDPtr); err != nil {
+			kingpin.Fatalf("%v", err)
+		}
+	}
+
+	// Keep stdout free for the data written in a dry run.
+	if dryRun && *outputPtr == "" {
+		if err := log.SetOutputPaths("stderr"); err != nil {
+			log.Fatalf("failed to configure logging: %v\n", err)
+		}
+	}
 
 	l, err := log.ParseLevel(*logLevelPtr)
 	if err != nil {
@@ -92,21 +237,71 @@ func main() {
 		}
 	}
 
-	h, err := telemetry.NewHarvester(
-		telemetry.ConfigAPIKey(*apiKeyPtr),
-		telemetry.ConfigCommonAttributes(commonAttrs),
-		telemetry.ConfigHarvestPeriod(*harvestPeriodPtr),
-		log.HarvesterConfigFunc(),
-		func(cfg *telemetry.Config) {
-			cfg.MetricsURLOverride = *metricsHostPtr
-			cfg.SpansURLOverride = *spansHostPtr
-		},
-	)
-	if err != nil {
-		log.Fatalf("failed to create harvester: %v\n", err)
+	account := newrelic.Account{
+		APIKey:      *apiKeyPtr,
+		AccountID:   *accountIDPtr,
+		MetricsHost: *metricsHostPtr,
+		SpansHost:   *spansHostPtr,
+		LogsHost:    *logsHostPtr,
+		EventsHost:  *eventsHostPtr,
 	}
 
+	checker := health.NewChecker(*failuresPtr)
+
+	var build newrelic.ClientBuilder
+	if dryRun {
+		w, err := openOutput(*outputPtr)
+		if err != nil {
+			log.Fatalf("failed to configure dry run: %v\n", err)
+		}
+		e := export.NewJSON(w, *harvestPeriodPtr, commonAttrs)
+		defer e.Close(context.Background())
+		build = func(newrelic.Account) (export.Exporter, *ingest.Harvester, error) {
+			return sharedExporter{e}, nil, nil
+		}
+		log.Infof("dry run: writing metrics and spans instead of sending them to New Relic, logs and events are dropped")
+	} else {
+		// Posts are recorded with the self metrics and checker, including
+		// the replays of spooled payloads.
+		transport := selfmetric.Transport(health.Transport(checker, nil))
+		if *spoolDirPtr != "" {
+			sp, err := spool.New(spool.Config{
+				Dir:         *spoolDirPtr,
+				MaxSize:     int64(*spoolMaxSizePtr),
+				MaxAge:      *spoolMaxAgePtr,
+				RetryPeriod: *harvestPeriodPtr,
+			}, transport)
+			if err != nil {
+				log.Fatalf("failed to configure spool: %v\n", err)
+			}
+			defer sp.Close()
+			transport = sp
+		}
+		build = func(a newrelic.Account) (export.Exporter, *ingest.Harvester, error) {
+			return buildClients(a, commonAttrs, transport)
+		}
+	}
+
+	serveHTTP(checker)
+
 	address := fmt.Sprintf(":%d", *portPtr)
+	cache := newrelic.HandlerCacheConfig{
+		Size:        *cacheSizePtr,
+		IdleTimeout: *idleTimeoutPtr,
+	}
+	queue := newrelic.QueueConfig{
+		Capacity: *queueCapPtr,
+		Workers:  *queueWorkersPtr,
+		Policy:   newrelic.QueuePolicy(*queuePolicyPtr),
+		OnSaturated: func(saturated bool) {
+			checker.SetSaturated("ingestion", saturated)
+		},
+	}
+
+	secrets := newrelic.SecretConfig{
+		Dir:       *secretsDirPtr,
+		EnvPrefix: *secretsEnvPtr,
+	}
 
 	var s *newrelic.Server
 	if *mtlsCertPtr != "" && *mtlsKeyPtr != "" {
@@ -114,22 +309,27 @@ func main() {
 		if err != nil {
 			log.Fatalf("failed to configure gRPC server TLS: %v\n", err)
 		}
-		s, err = newrelic.NewServer(address, h, so)
+		s, err = newrelic.NewServer(address, account, secrets, build, *harvestPeriodPtr, cache, queue, so)
 	} else {
-		s, err = newrelic.NewServer(address, h)
+		s, err = newrelic.NewServer(address, account, secrets, build, *harvestPeriodPtr, cache, queue)
 	}
 	if err != nil {
 		log.Fatalf("failed to start server: %v\n", err)
 	}
+	checker.OnChange(s.SetServing)
 
 	// Termination handler.
 	term := make(chan os.Signal, 1)
+	closed := make(chan struct{})
 	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
 	go func() {
+		defer close(closed)
 		select {
 		case <-term:
 			log.Infof("received SIGTERM, exiting gracefully...")
-			if err := s.Close(); err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), *shutdownPtr)
+			defer cancel()
+			if err := s.Close(ctx); err != nil {
 				log.Errorf("%v\n", err)
 			}
 		}
@@ -139,4 +339,7 @@ func main() {
 	if err := s.Wait(); err != nil {
 		log.Fatalf("%v\n", err)
 	}
+
+	// Wait for the recorded data to be sent.
+	<-closed
 }
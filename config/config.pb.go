@@ -6,21 +6,26 @@
 package config
 
 import (
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
 	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
+	_ "github.com/gogo/protobuf/types"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	io "io"
 	math "math"
 	reflect "reflect"
 	strconv "strconv"
 	strings "strings"
+	time "time"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
+var _ = time.Kitchen
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the proto package it is being compiled against.
@@ -28,6 +33,35 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+// New Relic data center regions.
+type Params_Region int32
+
+const (
+	// Default region. The endpoints the adapter was started with are
+	// used.
+	REGION_UNSPECIFIED Params_Region = 0
+	// The United States region.
+	US Params_Region = 1
+	// The European Union region.
+	EU Params_Region = 2
+)
+
+var Params_Region_name = map[int32]string{
+	0: "REGION_UNSPECIFIED",
+	1: "US",
+	2: "EU",
+}
+
+var Params_Region_value = map[string]int32{
+	"REGION_UNSPECIFIED": 0,
+	"US":                 1,
+	"EU":                 2,
+}
+
+func (Params_Region) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 0}
+}
+
 // New Relic Metric types.
 type Params_MetricInfo_Type int32
 
@@ -74,6 +108,20 @@ const (
 	//  * the duration and count of database transactions
 	//  * the time each message spent in a queue
 	SUMMARY Params_MetricInfo_Type = 3
+	// A histogram of the instance values.
+	//
+	// This metric type records the distribution of values within a time
+	// window by counting the values that fall within configured bucket
+	// boundaries. The bucket counts are reported as a New Relic `Count`
+	// metric named with a `.bucket` suffix and an `le` attribute set to
+	// the upper (inclusive) boundary of the bucket, with the final bucket
+	// having a boundary of `+Inf`. Bucket counts are cumulative, so they
+	// can be used to estimate percentiles (e.g. p95 or p99 latency).
+	// Alongside the buckets, the values are also reported as a New Relic
+	// `Summary` metric with the configured name.
+	//
+	// Either `buckets` or `exponential_buckets` must be specified.
+	HISTOGRAM Params_MetricInfo_Type = 4
 )
 
 var Params_MetricInfo_Type_name = map[int32]string{
@@ -81,6 +129,7 @@ var Params_MetricInfo_Type_name = map[int32]string{
 	1: "GAUGE",
 	2: "COUNT",
 	3: "SUMMARY",
+	4: "HISTOGRAM",
 }
 
 var Params_MetricInfo_Type_value = map[string]int32{
@@ -88,12 +137,52 @@ var Params_MetricInfo_Type_value = map[string]int32{
 	"GAUGE":       1,
 	"COUNT":       2,
 	"SUMMARY":     3,
+	"HISTOGRAM":   4,
 }
 
 func (Params_MetricInfo_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_cc332a44e926b360, []int{0, 0, 0}
 }
 
+// The formats the trace, span and parent span IDs of tracespan
+// instances are in.
+type Params_IdNormalization_Format int32
+
+const (
+	// The format of each ID is detected from its value.
+	AUTO Params_IdNormalization_Format = 0
+	// Hex IDs, as in the B3 multi headers, e.g. `X-B3-TraceId`, and
+	// Zipkin.
+	HEX Params_IdNormalization_Format = 1
+	// The value of the B3 single header,
+	// `{TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}`. The field of
+	// the header for the ID is used.
+	B3_SINGLE Params_IdNormalization_Format = 2
+	// The value of the W3C Trace Context `traceparent` header,
+	// `{version}-{trace-id}-{parent-id}-{trace-flags}`. The `trace-id`
+	// field is used for trace IDs and the `parent-id` field for span and
+	// parent span IDs.
+	TRACEPARENT Params_IdNormalization_Format = 3
+)
+
+var Params_IdNormalization_Format_name = map[int32]string{
+	0: "AUTO",
+	1: "HEX",
+	2: "B3_SINGLE",
+	3: "TRACEPARENT",
+}
+
+var Params_IdNormalization_Format_value = map[string]int32{
+	"AUTO":        0,
+	"HEX":         1,
+	"B3_SINGLE":   2,
+	"TRACEPARENT": 3,
+}
+
+func (Params_IdNormalization_Format) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 12, 0}
+}
+
 // Configuration format for the `newrelic` adapter.
 type Params struct {
 	// Optional. The namespace is used as a prefix for metric names in New Relic.
@@ -107,6 +196,93 @@ type Params struct {
 	// Any metric instances Istio sends to the adapter but not specified here
 	// will be dropped and not exported to New Relic.
 	Metrics map[string]*Params_MetricInfo `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Map of Istio logentry instance names and the corresponding New Relic
+	// LogInfo specification.
+	//
+	// Any logentry instances Istio sends to the adapter but not specified
+	// here will be dropped and not exported to New Relic.
+	Logs map[string]*Params_LogInfo `protobuf:"bytes,3,rep,name=logs,proto3" json:"logs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Optional. The maximum number of unique attribute sets recorded for
+	// each metric within a harvest period.
+	//
+	// Once the limit is reached, data points with new attribute sets are
+	// folded into a single overflow series for the metric that has the
+	// attribute `overflow` set to `true`. This guards against unbounded
+	// metric cardinality from high cardinality dimensions.
+	//
+	// If unspecified or zero, the number of attribute sets is not limited.
+	MetricCardinalityLimit int32 `protobuf:"varint,4,opt,name=metric_cardinality_limit,json=metricCardinalityLimit,proto3" json:"metric_cardinality_limit,omitempty"`
+	// Optional. Where the API key used to report data from this handler to
+	// New Relic is read from. This allows handlers to report to different
+	// New Relic accounts.
+	//
+	// If unspecified, the API key the adapter was started with is used.
+	ApiKeySecret *Params_ApiKeySecret `protobuf:"bytes,5,opt,name=api_key_secret,json=apiKeySecret,proto3" json:"api_key_secret,omitempty"`
+	// Optional. The ID of the New Relic account data from this handler is
+	// reported to. Edges are sent to the Event API of this account, which
+	// requires it.
+	//
+	// If unspecified, the account ID the adapter was started with is used,
+	// unless `api_key_secret` is specified.
+	AccountId string `protobuf:"bytes,20,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// Optional. The region of the New Relic account data from this handler
+	// is reported to. The region determines the endpoints data is sent to.
+	Region Params_Region `protobuf:"varint,6,opt,name=region,proto3,enum=adapter.newrelic.config.Params_Region" json:"region,omitempty"`
+	// Optional. Endpoint to send metrics to. Overrides the endpoint of the
+	// region. Requires `api_key_secret`.
+	MetricsHost string `protobuf:"bytes,7,opt,name=metrics_host,json=metricsHost,proto3" json:"metrics_host,omitempty"`
+	// Optional. Endpoint to send spans to. Overrides the endpoint of the
+	// region. Requires `api_key_secret`.
+	SpansHost string `protobuf:"bytes,8,opt,name=spans_host,json=spansHost,proto3" json:"spans_host,omitempty"`
+	// Optional. Endpoint to send logs to. Overrides the endpoint of the
+	// region. Requires `api_key_secret`.
+	LogsHost string `protobuf:"bytes,9,opt,name=logs_host,json=logsHost,proto3" json:"logs_host,omitempty"`
+	// Optional. Endpoint to send events to. Overrides the endpoint of the
+	// region. Requires `api_key_secret`.
+	EventsHost string `protobuf:"bytes,10,opt,name=events_host,json=eventsHost,proto3" json:"events_host,omitempty"`
+	// Optional. How tracespan instances are sampled.
+	//
+	// If unspecified, all spans are sent to New Relic.
+	TraceSampling *Params_TraceSampling `protobuf:"bytes,11,opt,name=trace_sampling,json=traceSampling,proto3" json:"trace_sampling,omitempty"`
+	// Optional. The rate limit applied to the spans of each service. Spans
+	// are attributed to the service of the destination, or of the source for
+	// client spans, as they are reported to New Relic. Spans in excess of the
+	// limit are dropped.
+	//
+	// If unspecified, spans are not limited unless the service has an
+	// override in `service_span_rate_limits`.
+	SpanRateLimit *Params_SpanRateLimit `protobuf:"bytes,12,opt,name=span_rate_limit,json=spanRateLimit,proto3" json:"span_rate_limit,omitempty"`
+	// Optional. Map of service names and the rate limit applied to their
+	// spans instead of `span_rate_limit`.
+	ServiceSpanRateLimits map[string]*Params_SpanRateLimit `protobuf:"bytes,13,rep,name=service_span_rate_limits,json=serviceSpanRateLimits,proto3" json:"service_span_rate_limits,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Optional. Enables deriving request metrics from tracespan instances,
+	// for meshes that do not configure metric instances. Each span received
+	// is counted in a `request.total` COUNT, in a `request.errors` COUNT if
+	// it is an error span, as decided by `error_spans`, and its duration is
+	// recorded in a `request.duration.milliseconds` SUMMARY. These have the
+	// `service.name`, `span.name`, `response.code` and `reporter` attributes.
+	//
+	// Metrics are derived from every span received, before spans are
+	// sampled or rate limited.
+	SpanMetrics *Params_SpanMetrics `protobuf:"bytes,14,opt,name=span_metrics,json=spanMetrics,proto3" json:"span_metrics,omitempty"`
+	// Optional. Configures the attributes of spans.
+	Traces *Params_Traces `protobuf:"bytes,15,opt,name=traces,proto3" json:"traces,omitempty"`
+	// Optional. Configures the service name of spans, e.g. to report spans of
+	// a logical service instead of its pods.
+	ServiceName *Params_ServiceName `protobuf:"bytes,16,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Optional. Configures the spans marked as errors.
+	ErrorSpans *Params_ErrorSpans `protobuf:"bytes,17,opt,name=error_spans,json=errorSpans,proto3" json:"error_spans,omitempty"`
+	// Optional. Enables the validation and canonicalization of trace and
+	// span IDs. Spans with malformed IDs are dropped and counted by reason by
+	// the `newrelic.istio.adapter.conversion.errors` self metric.
+	//
+	// If unspecified, IDs are sent as received.
+	IdNormalization *Params_IdNormalization `protobuf:"bytes,18,opt,name=id_normalization,json=idNormalization,proto3" json:"id_normalization,omitempty"`
+	// Optional. The phases of requests derived from span tags. A phase span
+	// is sent for each phase whose span tags are present, clamped to the span
+	// of the request. Phase spans are sampled with their trace and count
+	// toward span rate limits.
+	SpanPhases []*Params_SpanPhase `protobuf:"bytes,19,rep,name=span_phases,json=spanPhases,proto3" json:"span_phases,omitempty"`
 }
 
 func (m *Params) Reset()      { *m = Params{} }
@@ -155,6 +331,132 @@ func (m *Params) GetMetrics() map[string]*Params_MetricInfo {
 	return nil
 }
 
+func (m *Params) GetLogs() map[string]*Params_LogInfo {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+func (m *Params) GetMetricCardinalityLimit() int32 {
+	if m != nil {
+		return m.MetricCardinalityLimit
+	}
+	return 0
+}
+
+func (m *Params) GetApiKeySecret() *Params_ApiKeySecret {
+	if m != nil {
+		return m.ApiKeySecret
+	}
+	return nil
+}
+
+func (m *Params) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *Params) GetRegion() Params_Region {
+	if m != nil {
+		return m.Region
+	}
+	return REGION_UNSPECIFIED
+}
+
+func (m *Params) GetMetricsHost() string {
+	if m != nil {
+		return m.MetricsHost
+	}
+	return ""
+}
+
+func (m *Params) GetSpansHost() string {
+	if m != nil {
+		return m.SpansHost
+	}
+	return ""
+}
+
+func (m *Params) GetLogsHost() string {
+	if m != nil {
+		return m.LogsHost
+	}
+	return ""
+}
+
+func (m *Params) GetEventsHost() string {
+	if m != nil {
+		return m.EventsHost
+	}
+	return ""
+}
+
+func (m *Params) GetTraceSampling() *Params_TraceSampling {
+	if m != nil {
+		return m.TraceSampling
+	}
+	return nil
+}
+
+func (m *Params) GetSpanRateLimit() *Params_SpanRateLimit {
+	if m != nil {
+		return m.SpanRateLimit
+	}
+	return nil
+}
+
+func (m *Params) GetServiceSpanRateLimits() map[string]*Params_SpanRateLimit {
+	if m != nil {
+		return m.ServiceSpanRateLimits
+	}
+	return nil
+}
+
+func (m *Params) GetSpanMetrics() *Params_SpanMetrics {
+	if m != nil {
+		return m.SpanMetrics
+	}
+	return nil
+}
+
+func (m *Params) GetTraces() *Params_Traces {
+	if m != nil {
+		return m.Traces
+	}
+	return nil
+}
+
+func (m *Params) GetServiceName() *Params_ServiceName {
+	if m != nil {
+		return m.ServiceName
+	}
+	return nil
+}
+
+func (m *Params) GetErrorSpans() *Params_ErrorSpans {
+	if m != nil {
+		return m.ErrorSpans
+	}
+	return nil
+}
+
+func (m *Params) GetIdNormalization() *Params_IdNormalization {
+	if m != nil {
+		return m.IdNormalization
+	}
+	return nil
+}
+
+func (m *Params) GetSpanPhases() []*Params_SpanPhase {
+	if m != nil {
+		return m.SpanPhases
+	}
+	return nil
+}
+
 // Describes how to represent an Istio metric instance in New Relic.
 type Params_MetricInfo struct {
 	// Recommended. The name of the metric (scoped by namespaces) in New Relic.
@@ -166,6 +468,65 @@ type Params_MetricInfo struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Required. New Relic metric type to interpret the Istio instance as.
 	Type Params_MetricInfo_Type `protobuf:"varint,2,opt,name=type,proto3,enum=adapter.newrelic.config.Params_MetricInfo_Type" json:"type,omitempty"`
+	// Optional. Dimension names to send to New Relic as attributes.
+	//
+	// Entries may be glob patterns, e.g. `source.*`. If specified, only
+	// dimensions matching at least one of the entries are sent. If
+	// unspecified, all dimensions are sent.
+	IncludeAttributes []string `protobuf:"bytes,3,rep,name=include_attributes,json=includeAttributes,proto3" json:"include_attributes,omitempty"`
+	// Optional. Dimension names to never send to New Relic as attributes.
+	//
+	// Entries may be glob patterns, e.g. `request.*`. Exclusions are
+	// applied after inclusions, so a dimension matching both lists is
+	// dropped.
+	//
+	// This is useful to reduce metric cardinality by removing high
+	// cardinality dimensions, like `request.path` or `destination.ip`,
+	// without editing the Istio metric instances.
+	ExcludeAttributes []string `protobuf:"bytes,4,rep,name=exclude_attributes,json=excludeAttributes,proto3" json:"exclude_attributes,omitempty"`
+	// Optional. The upper boundaries of the buckets of a `HISTOGRAM`
+	// metric. The boundaries must be in strictly increasing order and there
+	// may be at most 200 of them.
+	//
+	// Only one of `buckets` or `exponential_buckets` may be specified and
+	// only for a `HISTOGRAM` metric.
+	Buckets []float64 `protobuf:"fixed64,5,rep,packed,name=buckets,proto3" json:"buckets,omitempty"`
+	// Optional. Exponentially growing bucket boundaries of a `HISTOGRAM`
+	// metric. For example, a `count` of 4, `start` of 1, and `factor` of 2
+	// results in the boundaries 1, 2, 4, and 8.
+	//
+	// Only one of `buckets` or `exponential_buckets` may be specified and
+	// only for a `HISTOGRAM` metric.
+	ExponentialBuckets *Params_MetricInfo_ExponentialBuckets `protobuf:"bytes,6,opt,name=exponential_buckets,json=exponentialBuckets,proto3" json:"exponential_buckets,omitempty"`
+	// Optional. Percentiles of the values of a `SUMMARY` metric to report,
+	// e.g. `[50, 90, 99]`. Each percentile must be greater than 0 and less
+	// than or equal to 100.
+	//
+	// Percentiles are computed by the adapter from a sketch of the values
+	// with each attribute set and reported every harvest period as a New
+	// Relic `Gauge` metric. The gauge is named after the metric with a
+	// `.p` and percentile suffix, e.g. `istio.request.duration.p99`.
+	Percentiles []float64 `protobuf:"fixed64,7,rep,packed,name=percentiles,proto3" json:"percentiles,omitempty"`
+	// Optional. The maximum number of bins the sketch used to compute
+	// percentiles keeps for each attribute set. This bounds the memory
+	// used for each series to roughly 16 bytes per bin. Once a sketch
+	// reaches this size, the accuracy of the lowest percentiles degrades.
+	//
+	// If unspecified or zero, a maximum of 1024 bins is used.
+	SketchMaxBins int32 `protobuf:"varint,8,opt,name=sketch_max_bins,json=sketchMaxBins,proto3" json:"sketch_max_bins,omitempty"`
+	// Optional. The unit of the metric values. Supported units are:
+	//
+	//  * time: `ns`, `us`, `ms`, `s`, `min`, `h`
+	//  * data: `bytes`, `KB`, `MB`, `GB`, `KiB`, `MiB`, `GiB`
+	//
+	// Duration values are converted to the unit, which must be a time
+	// unit. Numeric values are assumed to be milliseconds for time units
+	// and bytes for data units and are converted to the unit. The unit is
+	// also sent with the metric as the `unit` attribute.
+	//
+	// If unspecified, durations are sent as milliseconds, numeric values
+	// are sent unchanged, and no `unit` attribute is sent.
+	Unit string `protobuf:"bytes,9,opt,name=unit,proto3" json:"unit,omitempty"`
 }
 
 func (m *Params_MetricInfo) Reset()      { *m = Params_MetricInfo{} }
@@ -214,336 +575,6112 @@ func (m *Params_MetricInfo) GetType() Params_MetricInfo_Type {
 	return UNSPECIFIED
 }
 
-func init() {
-	proto.RegisterEnum("adapter.newrelic.config.Params_MetricInfo_Type", Params_MetricInfo_Type_name, Params_MetricInfo_Type_value)
-	proto.RegisterType((*Params)(nil), "adapter.newrelic.config.Params")
-	proto.RegisterMapType((map[string]*Params_MetricInfo)(nil), "adapter.newrelic.config.Params.MetricsEntry")
-	proto.RegisterType((*Params_MetricInfo)(nil), "adapter.newrelic.config.Params.MetricInfo")
+func (m *Params_MetricInfo) GetIncludeAttributes() []string {
+	if m != nil {
+		return m.IncludeAttributes
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("config/config.proto", fileDescriptor_cc332a44e926b360) }
-
-var fileDescriptor_cc332a44e926b360 = []byte{
-	// 356 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x51, 0xbf, 0x4f, 0xea, 0x40,
-	0x1c, 0xbf, 0x2b, 0xbf, 0xc2, 0xb7, 0x2f, 0xef, 0x35, 0xf7, 0x5e, 0xf2, 0x08, 0x31, 0x17, 0xc2,
-	0x44, 0x8c, 0x39, 0x12, 0x5c, 0x0c, 0x71, 0x10, 0xb1, 0x10, 0x06, 0x90, 0x14, 0x3a, 0xe8, 0x76,
-	0xd6, 0x83, 0x10, 0xa1, 0x6d, 0x4a, 0xd5, 0x74, 0xf3, 0x4f, 0xf0, 0x5f, 0x70, 0xf3, 0x4f, 0x71,
-	0x64, 0x64, 0x94, 0x63, 0x71, 0x64, 0x76, 0x32, 0xed, 0x61, 0x74, 0x31, 0x61, 0xba, 0xcf, 0xdd,
-	0xe7, 0x67, 0x72, 0xf0, 0xd7, 0xf1, 0xdc, 0xd1, 0x64, 0x5c, 0x55, 0x07, 0xf3, 0x03, 0x2f, 0xf4,
-	0xc8, 0x7f, 0x7e, 0xcd, 0xfd, 0x50, 0x04, 0xcc, 0x15, 0xf7, 0x81, 0x98, 0x4e, 0x1c, 0xa6, 0xe8,
-	0xe2, 0xbf, 0xb1, 0x37, 0xf6, 0x12, 0x4d, 0x35, 0x46, 0x4a, 0x5e, 0x7e, 0xd7, 0x20, 0xdb, 0xe7,
-	0x01, 0x9f, 0xcd, 0xc9, 0x1e, 0xe4, 0x5d, 0x3e, 0x13, 0x73, 0x9f, 0x3b, 0xa2, 0x80, 0x4b, 0xb8,
-	0x92, 0xb7, 0xbe, 0x1e, 0x48, 0x0b, 0x72, 0x33, 0x11, 0x06, 0x13, 0x67, 0x5e, 0xd0, 0x4a, 0xa9,
-	0x8a, 0x5e, 0x3b, 0x60, 0x3f, 0x34, 0x31, 0x95, 0xc7, 0xba, 0x4a, 0x6e, 0xba, 0x61, 0x10, 0x59,
-	0x9f, 0xe6, 0xe2, 0x13, 0x06, 0x50, 0x4c, 0xc7, 0x1d, 0x79, 0x84, 0x40, 0x3a, 0xee, 0xd8, 0xf6,
-	0x25, 0x98, 0x34, 0x21, 0x1d, 0x46, 0xbe, 0x28, 0x68, 0x25, 0x5c, 0xf9, 0x5d, 0xab, 0xee, 0xd6,
-	0x13, 0xa7, 0xb1, 0x61, 0xe4, 0x0b, 0x2b, 0x31, 0x97, 0xeb, 0x90, 0x8e, 0x6f, 0xe4, 0x0f, 0xe8,
-	0x76, 0x6f, 0xd0, 0x37, 0x9b, 0x9d, 0x56, 0xc7, 0x3c, 0x33, 0x10, 0xc9, 0x43, 0xa6, 0xdd, 0xb0,
-	0xdb, 0xa6, 0x81, 0x63, 0xd8, 0x3c, 0xb7, 0x7b, 0x43, 0x43, 0x23, 0x3a, 0xe4, 0x06, 0x76, 0xb7,
-	0xdb, 0xb0, 0x2e, 0x8c, 0x54, 0x71, 0x04, 0xbf, 0xbe, 0x8f, 0x27, 0x06, 0xa4, 0x6e, 0x44, 0xb4,
-	0xdd, 0x18, 0x43, 0x72, 0x02, 0x99, 0x3b, 0x3e, 0xbd, 0x55, 0x1b, 0xf5, 0xda, 0xfe, 0xee, 0x1b,
-	0x2d, 0x65, 0xac, 0x6b, 0x47, 0xf8, 0xf4, 0x78, 0xb1, 0xa2, 0x68, 0xb9, 0xa2, 0x68, 0xb3, 0xa2,
-	0xf8, 0x41, 0x52, 0xfc, 0x2c, 0x29, 0x7e, 0x91, 0x14, 0x2f, 0x24, 0xc5, 0xaf, 0x92, 0xe2, 0x37,
-	0x49, 0xd1, 0x46, 0x52, 0xfc, 0xb8, 0xa6, 0x68, 0xb1, 0xa6, 0x68, 0xb9, 0xa6, 0xe8, 0x32, 0xab,
-	0xa2, 0xaf, 0xb2, 0xc9, 0x0f, 0x1e, 0x7e, 0x04, 0x00, 0x00, 0xff, 0xff, 0xbf, 0xc2, 0xac, 0xdf,
-	0x07, 0x02, 0x00, 0x00,
+func (m *Params_MetricInfo) GetExcludeAttributes() []string {
+	if m != nil {
+		return m.ExcludeAttributes
+	}
+	return nil
 }
 
-func (x Params_MetricInfo_Type) String() string {
-	s, ok := Params_MetricInfo_Type_name[int32(x)]
-	if ok {
-		return s
+func (m *Params_MetricInfo) GetBuckets() []float64 {
+	if m != nil {
+		return m.Buckets
 	}
-	return strconv.Itoa(int(x))
+	return nil
 }
-func (this *Params) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*Params)
-	if !ok {
-		that2, ok := that.(Params)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Namespace != that1.Namespace {
-		return false
+func (m *Params_MetricInfo) GetExponentialBuckets() *Params_MetricInfo_ExponentialBuckets {
+	if m != nil {
+		return m.ExponentialBuckets
 	}
-	if len(this.Metrics) != len(that1.Metrics) {
-		return false
+	return nil
+}
+
+func (m *Params_MetricInfo) GetPercentiles() []float64 {
+	if m != nil {
+		return m.Percentiles
 	}
-	for i := range this.Metrics {
-		if !this.Metrics[i].Equal(that1.Metrics[i]) {
-			return false
-		}
+	return nil
+}
+
+func (m *Params_MetricInfo) GetSketchMaxBins() int32 {
+	if m != nil {
+		return m.SketchMaxBins
 	}
-	return true
+	return 0
 }
-func (this *Params_MetricInfo) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *Params_MetricInfo) GetUnit() string {
+	if m != nil {
+		return m.Unit
 	}
+	return ""
+}
 
-	that1, ok := that.(*Params_MetricInfo)
-	if !ok {
-		that2, ok := that.(Params_MetricInfo)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// Describes a sequence of exponentially growing bucket boundaries.
+type Params_MetricInfo_ExponentialBuckets struct {
+	// Required. The number of bucket boundaries. Must be greater than 0
+	// and at most 200.
+	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Required. The first bucket boundary. Must be greater than 0.
+	Start float64 `protobuf:"fixed64,2,opt,name=start,proto3" json:"start,omitempty"`
+	// Required. The factor each bucket boundary is multiplied by to get
+	// the next boundary. Must be greater than 1.
+	Factor float64 `protobuf:"fixed64,3,opt,name=factor,proto3" json:"factor,omitempty"`
+}
+
+func (m *Params_MetricInfo_ExponentialBuckets) Reset()      { *m = Params_MetricInfo_ExponentialBuckets{} }
+func (*Params_MetricInfo_ExponentialBuckets) ProtoMessage() {}
+func (*Params_MetricInfo_ExponentialBuckets) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 0, 0}
+}
+func (m *Params_MetricInfo_ExponentialBuckets) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_MetricInfo_ExponentialBuckets) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_MetricInfo_ExponentialBuckets.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+}
+func (m *Params_MetricInfo_ExponentialBuckets) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_MetricInfo_ExponentialBuckets.Merge(m, src)
+}
+func (m *Params_MetricInfo_ExponentialBuckets) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_MetricInfo_ExponentialBuckets) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_MetricInfo_ExponentialBuckets.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_MetricInfo_ExponentialBuckets proto.InternalMessageInfo
+
+func (m *Params_MetricInfo_ExponentialBuckets) GetCount() int32 {
+	if m != nil {
+		return m.Count
 	}
-	if this.Name != that1.Name {
-		return false
+	return 0
+}
+
+func (m *Params_MetricInfo_ExponentialBuckets) GetStart() float64 {
+	if m != nil {
+		return m.Start
 	}
-	if this.Type != that1.Type {
-		return false
+	return 0
+}
+
+func (m *Params_MetricInfo_ExponentialBuckets) GetFactor() float64 {
+	if m != nil {
+		return m.Factor
 	}
-	return true
+	return 0
 }
-func (this *Params) GoString() string {
-	if this == nil {
-		return "nil"
+
+// Describes how to represent an Istio logentry instance in New Relic.
+type Params_LogInfo struct {
+	// Optional. A Go text/template used to build the log message from the
+	// logentry instance variables. Variables are referenced by name, e.g.
+	// `{{.method}} {{.url}} {{.responseCode}}`.
+	//
+	// If unspecified, the value of the `message` variable is used, if one
+	// exists. Otherwise, the message is left empty and only attributes are
+	// sent.
+	MessageTemplate string `protobuf:"bytes,1,opt,name=message_template,json=messageTemplate,proto3" json:"message_template,omitempty"`
+	// Optional. Mapping of logentry instance severity values to the
+	// `level` attribute reported to New Relic. For example, a mapping of
+	// `warning: warn` reports entries with a severity of `warning` as `warn`.
+	//
+	// Severities not found in the mapping are reported unchanged.
+	SeverityLevels map[string]string `protobuf:"bytes,2,rep,name=severity_levels,json=severityLevels,proto3" json:"severity_levels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *Params_LogInfo) Reset()      { *m = Params_LogInfo{} }
+func (*Params_LogInfo) ProtoMessage() {}
+func (*Params_LogInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 2}
+}
+func (m *Params_LogInfo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_LogInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_LogInfo.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&config.Params{")
-	s = append(s, "Namespace: "+fmt.Sprintf("%#v", this.Namespace)+",\n")
-	keysForMetrics := make([]string, 0, len(this.Metrics))
-	for k, _ := range this.Metrics {
-		keysForMetrics = append(keysForMetrics, k)
-	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForMetrics)
-	mapStringForMetrics := "map[string]*Params_MetricInfo{"
-	for _, k := range keysForMetrics {
-		mapStringForMetrics += fmt.Sprintf("%#v: %#v,", k, this.Metrics[k])
-	}
-	mapStringForMetrics += "}"
-	if this.Metrics != nil {
-		s = append(s, "Metrics: "+mapStringForMetrics+",\n")
+}
+func (m *Params_LogInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_LogInfo.Merge(m, src)
+}
+func (m *Params_LogInfo) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_LogInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_LogInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_LogInfo proto.InternalMessageInfo
+
+func (m *Params_LogInfo) GetMessageTemplate() string {
+	if m != nil {
+		return m.MessageTemplate
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return ""
 }
-func (this *Params_MetricInfo) GoString() string {
-	if this == nil {
-		return "nil"
+
+func (m *Params_LogInfo) GetSeverityLevels() map[string]string {
+	if m != nil {
+		return m.SeverityLevels
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&config.Params_MetricInfo{")
-	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
-	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return nil
 }
-func valueToGoStringConfig(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+// Describes where a New Relic API key is read from. Exactly one of
+// `env` or `file` must be specified.
+type Params_ApiKeySecret struct {
+	// The name of an environment variable of the adapter holding the API
+	// key. It must start with the `--api-key-env-prefix` of the adapter.
+	Env string `protobuf:"bytes,1,opt,name=env,proto3" json:"env,omitempty"`
+	// The path of a file holding the API key, e.g. a key of a Kubernetes
+	// secret mounted in the adapter. It must be in, or relative to, the
+	// `--api-key-secrets-dir` of the adapter. Leading and trailing
+	// whitespace is ignored.
+	File string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+}
+
+func (m *Params_ApiKeySecret) Reset()      { *m = Params_ApiKeySecret{} }
+func (*Params_ApiKeySecret) ProtoMessage() {}
+func (*Params_ApiKeySecret) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 4}
+}
+func (m *Params_ApiKeySecret) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_ApiKeySecret) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_ApiKeySecret.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
 }
-func (m *Params) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *Params_ApiKeySecret) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_ApiKeySecret.Merge(m, src)
+}
+func (m *Params_ApiKeySecret) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_ApiKeySecret) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_ApiKeySecret.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_ApiKeySecret proto.InternalMessageInfo
+
+func (m *Params_ApiKeySecret) GetEnv() string {
+	if m != nil {
+		return m.Env
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Params) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Namespace) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.Namespace)))
-		i += copy(dAtA[i:], m.Namespace)
+func (m *Params_ApiKeySecret) GetFile() string {
+	if m != nil {
+		return m.File
 	}
-	if len(m.Metrics) > 0 {
-		for k, _ := range m.Metrics {
-			dAtA[i] = 0x12
-			i++
-			v := m.Metrics[k]
-			msgSize := 0
-			if v != nil {
-				msgSize = v.Size()
-				msgSize += 1 + sovConfig(uint64(msgSize))
-			}
-			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + msgSize
-			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
-			i += copy(dAtA[i:], k)
-			if v != nil {
-				dAtA[i] = 0x12
-				i++
-				i = encodeVarintConfig(dAtA, i, uint64(v.Size()))
-				n1, err1 := v.MarshalTo(dAtA[i:])
-				if err1 != nil {
-					return 0, err1
-				}
-				i += n1
-			}
+	return ""
+}
+
+// Describes how tracespan instances are sampled before being sent to
+// New Relic. Sampling decisions are made per trace, so the spans of a
+// trace are kept or dropped together.
+type Params_TraceSampling struct {
+	// Optional. The percentage of traces kept, greater than 0 and at most
+	// 100. Traces are kept based on a hash of their trace ID, so every
+	// adapter replica makes the same decision for a trace.
+	//
+	// If unspecified or zero, all traces are kept.
+	HeadPercentage float64 `protobuf:"fixed64,1,opt,name=head_percentage,json=headPercentage,proto3" json:"head_percentage,omitempty"`
+	// Optional. Enables tail sampling. Traces with an error span, as
+	// decided by `error_spans`, or a span exceeding the latency threshold
+	// are always kept. Other traces are kept according
+	// to `head_percentage`.
+	//
+	// Spans of a trace received after it was decided upon follow the
+	// decision for another window.
+	Tail *Params_TraceSampling_TailSampling `protobuf:"bytes,2,opt,name=tail,proto3" json:"tail,omitempty"`
+}
+
+func (m *Params_TraceSampling) Reset()      { *m = Params_TraceSampling{} }
+func (*Params_TraceSampling) ProtoMessage() {}
+func (*Params_TraceSampling) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 5}
+}
+func (m *Params_TraceSampling) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_TraceSampling) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_TraceSampling.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *Params_TraceSampling) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_TraceSampling.Merge(m, src)
+}
+func (m *Params_TraceSampling) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_TraceSampling) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_TraceSampling.DiscardUnknown(m)
 }
 
-func (m *Params_MetricInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_Params_TraceSampling proto.InternalMessageInfo
+
+func (m *Params_TraceSampling) GetHeadPercentage() float64 {
+	if m != nil {
+		return m.HeadPercentage
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Params_MetricInfo) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Name) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.Name)))
-		i += copy(dAtA[i:], m.Name)
+func (m *Params_TraceSampling) GetTail() *Params_TraceSampling_TailSampling {
+	if m != nil {
+		return m.Tail
 	}
-	if m.Type != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintConfig(dAtA, i, uint64(m.Type))
+	return nil
+}
+
+// Describes how spans are buffered to decide whether a trace is kept
+// once its spans have been seen.
+type Params_TraceSampling_TailSampling struct {
+	// Required. How long the spans of a trace are buffered after its
+	// first span is received before the trace is decided upon, e.g.
+	// `10s`. Must be at least `1s`.
+	Window time.Duration `protobuf:"bytes,1,opt,name=window,proto3,stdduration" json:"window"`
+	// Optional. Traces with a span taking longer than this are always
+	// kept.
+	//
+	// If unspecified or zero, span durations are not considered.
+	LatencyThreshold time.Duration `protobuf:"bytes,2,opt,name=latency_threshold,json=latencyThreshold,proto3,stdduration" json:"latency_threshold"`
+	// Optional. The maximum number of traces buffered at once. Once
+	// reached, the oldest buffered trace is decided upon early.
+	//
+	// If unspecified or zero, the number of traces is not limited.
+	MaxTraces int32 `protobuf:"varint,3,opt,name=max_traces,json=maxTraces,proto3" json:"max_traces,omitempty"`
+}
+
+func (m *Params_TraceSampling_TailSampling) Reset()      { *m = Params_TraceSampling_TailSampling{} }
+func (*Params_TraceSampling_TailSampling) ProtoMessage() {}
+func (*Params_TraceSampling_TailSampling) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 5, 0}
+}
+func (m *Params_TraceSampling_TailSampling) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_TraceSampling_TailSampling) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_TraceSampling_TailSampling.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *Params_TraceSampling_TailSampling) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_TraceSampling_TailSampling.Merge(m, src)
+}
+func (m *Params_TraceSampling_TailSampling) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_TraceSampling_TailSampling) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_TraceSampling_TailSampling.DiscardUnknown(m)
 }
 
-func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+var xxx_messageInfo_Params_TraceSampling_TailSampling proto.InternalMessageInfo
+
+func (m *Params_TraceSampling_TailSampling) GetWindow() time.Duration {
+	if m != nil {
+		return m.Window
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return 0
 }
-func (m *Params) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (m *Params_TraceSampling_TailSampling) GetLatencyThreshold() time.Duration {
+	if m != nil {
+		return m.LatencyThreshold
 	}
-	var l int
-	_ = l
-	l = len(m.Namespace)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	return 0
+}
+
+func (m *Params_TraceSampling_TailSampling) GetMaxTraces() int32 {
+	if m != nil {
+		return m.MaxTraces
 	}
-	if len(m.Metrics) > 0 {
-		for k, v := range m.Metrics {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovConfig(uint64(l))
-			}
-			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+	return 0
+}
+
+// Describes a token bucket limiting the rate of spans sent to New Relic.
+type Params_SpanRateLimit struct {
+	// Optional. The sustained number of spans per second sent for a
+	// service.
+	//
+	// If unspecified or zero, the spans of the service are not limited.
+	SpansPerSecond float64 `protobuf:"fixed64,1,opt,name=spans_per_second,json=spansPerSecond,proto3" json:"spans_per_second,omitempty"`
+	// Optional. The number of spans that can be sent at once for a service
+	// in excess of the sustained rate.
+	//
+	// If unspecified or zero, the burst is `spans_per_second` rounded up.
+	Burst int32 `protobuf:"varint,2,opt,name=burst,proto3" json:"burst,omitempty"`
+}
+
+func (m *Params_SpanRateLimit) Reset()      { *m = Params_SpanRateLimit{} }
+func (*Params_SpanRateLimit) ProtoMessage() {}
+func (*Params_SpanRateLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 6}
+}
+func (m *Params_SpanRateLimit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_SpanRateLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_SpanRateLimit.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *Params_SpanRateLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_SpanRateLimit.Merge(m, src)
+}
+func (m *Params_SpanRateLimit) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_SpanRateLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_SpanRateLimit.DiscardUnknown(m)
 }
 
-func (m *Params_MetricInfo) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+var xxx_messageInfo_Params_SpanRateLimit proto.InternalMessageInfo
+
+func (m *Params_SpanRateLimit) GetSpansPerSecond() float64 {
+	if m != nil {
+		return m.SpansPerSecond
 	}
-	if m.Type != 0 {
-		n += 1 + sovConfig(uint64(m.Type))
+	return 0
+}
+
+func (m *Params_SpanRateLimit) GetBurst() int32 {
+	if m != nil {
+		return m.Burst
 	}
-	return n
+	return 0
 }
 
-func sovConfig(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+// Describes the request metrics derived from tracespan instances.
+type Params_SpanMetrics struct {
+	// Optional. The namespace prefixing the names of the derived metrics.
+	//
+	// If unspecified, the handler `namespace` followed by `span` is used,
+	// e.g. `istio.span`.
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+}
+
+func (m *Params_SpanMetrics) Reset()      { *m = Params_SpanMetrics{} }
+func (*Params_SpanMetrics) ProtoMessage() {}
+func (*Params_SpanMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 8}
+}
+func (m *Params_SpanMetrics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_SpanMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_SpanMetrics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params_SpanMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_SpanMetrics.Merge(m, src)
+}
+func (m *Params_SpanMetrics) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_SpanMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_SpanMetrics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_SpanMetrics proto.InternalMessageInfo
+
+func (m *Params_SpanMetrics) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+// Describes how the attributes of spans converted from tracespan
+// instances are named and which are sent.
+type Params_Traces struct {
+	// Optional. Send span attributes named with the conventions of New
+	// Relic APM agents, so Istio spans line up with agent spans in
+	// distributed tracing.
+	//
+	// `response.code` is sent as `http.statusCode` and the `request.method`
+	// span tag, if any, as `http.method`. `peer.address` is set to the
+	// destination IP address and `destination.port` span tag of client
+	// spans, and to the source IP address of server spans. The `error`
+	// attribute is set on all error spans, see `error_spans`.
+	NewRelicConventions bool `protobuf:"varint,1,opt,name=new_relic_conventions,json=newRelicConventions,proto3" json:"new_relic_conventions,omitempty"`
+	// Optional. Map of span attribute names, including span tags, and the
+	// names they are sent as instead. An attribute mapped to an empty name
+	// is not sent.
+	//
+	// Attributes are renamed after the `new_relic_conventions` are
+	// applied.
+	RenameAttributes map[string]string `protobuf:"bytes,2,rep,name=rename_attributes,json=renameAttributes,proto3" json:"rename_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Optional. Span attribute names to send to New Relic.
+	//
+	// Entries may be glob patterns, e.g. `source.*`, and match the names
+	// attributes are sent as. If specified, only attributes matching at
+	// least one of the entries are sent. If unspecified, all attributes
+	// are sent.
+	IncludeAttributes []string `protobuf:"bytes,3,rep,name=include_attributes,json=includeAttributes,proto3" json:"include_attributes,omitempty"`
+	// Optional. Span attribute names to never send to New Relic.
+	//
+	// Entries may be glob patterns, e.g. `request.*`, and match the names
+	// attributes are sent as. Exclusions are applied after inclusions, so
+	// an attribute matching both lists is not sent.
+	ExcludeAttributes []string `protobuf:"bytes,4,rep,name=exclude_attributes,json=excludeAttributes,proto3" json:"exclude_attributes,omitempty"`
+}
+
+func (m *Params_Traces) Reset()      { *m = Params_Traces{} }
+func (*Params_Traces) ProtoMessage() {}
+func (*Params_Traces) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 9}
+}
+func (m *Params_Traces) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_Traces) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_Traces.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params_Traces) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_Traces.Merge(m, src)
+}
+func (m *Params_Traces) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_Traces) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_Traces.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_Traces proto.InternalMessageInfo
+
+func (m *Params_Traces) GetNewRelicConventions() bool {
+	if m != nil {
+		return m.NewRelicConventions
+	}
+	return false
+}
+
+func (m *Params_Traces) GetRenameAttributes() map[string]string {
+	if m != nil {
+		return m.RenameAttributes
+	}
+	return nil
+}
+
+func (m *Params_Traces) GetIncludeAttributes() []string {
+	if m != nil {
+		return m.IncludeAttributes
+	}
+	return nil
+}
+
+func (m *Params_Traces) GetExcludeAttributes() []string {
+	if m != nil {
+		return m.ExcludeAttributes
+	}
+	return nil
+}
+
+// Describes how the service name of spans is built from their attributes.
+//
+// Each entry is a template of span attribute names, including span tags,
+// in braces along with literal text, e.g.
+// `{destination.workload.name}.{destination.workload.namespace}`. The
+// first template whose attributes are all present and non-empty is used.
+type Params_ServiceName struct {
+	// Optional. Templates of the service name of server spans.
+	//
+	// If unspecified, or no template applies, the destination name is used.
+	Server []string `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
+	// Optional. Templates of the service name of client spans.
+	//
+	// If unspecified, or no template applies, the source name is used.
+	Client []string `protobuf:"bytes,2,rep,name=client,proto3" json:"client,omitempty"`
+}
+
+func (m *Params_ServiceName) Reset()      { *m = Params_ServiceName{} }
+func (*Params_ServiceName) ProtoMessage() {}
+func (*Params_ServiceName) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 10}
+}
+func (m *Params_ServiceName) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_ServiceName) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_ServiceName.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params_ServiceName) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_ServiceName.Merge(m, src)
+}
+func (m *Params_ServiceName) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_ServiceName) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_ServiceName.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_ServiceName proto.InternalMessageInfo
+
+func (m *Params_ServiceName) GetServer() []string {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *Params_ServiceName) GetClient() []string {
+	if m != nil {
+		return m.Client
+	}
+	return nil
+}
+
+// Describes which spans are marked as errors with the `error` attribute,
+// in addition to spans with an HTTP status code of 500 or greater.
+type Params_ErrorSpans struct {
+	// Optional. Mark spans with an HTTP status code from 400 to 499 as
+	// errors.
+	ClientErrors bool `protobuf:"varint,1,opt,name=client_errors,json=clientErrors,proto3" json:"client_errors,omitempty"`
+	// Optional. The span tag holding the gRPC status code of a request,
+	// e.g. `response.grpc_status`. Spans with a status code other than `0`
+	// or `OK` are marked as errors.
+	GrpcStatusTag string `protobuf:"bytes,2,opt,name=grpc_status_tag,json=grpcStatusTag,proto3" json:"grpc_status_tag,omitempty"`
+	// Optional. The Envoy response flags marking spans as errors, e.g.
+	// `UH`, `UF` or `URX`.
+	ResponseFlags []string `protobuf:"bytes,3,rep,name=response_flags,json=responseFlags,proto3" json:"response_flags,omitempty"`
+	// Optional. The span tag holding the comma separated Envoy response
+	// flags of a request.
+	//
+	// If unspecified, `response.flags` is used.
+	ResponseFlagsTag string `protobuf:"bytes,4,opt,name=response_flags_tag,json=responseFlagsTag,proto3" json:"response_flags_tag,omitempty"`
+	// Optional. The span tag whose value is sent as the `error.message`
+	// attribute of error spans.
+	MessageTag string `protobuf:"bytes,5,opt,name=message_tag,json=messageTag,proto3" json:"message_tag,omitempty"`
+}
+
+func (m *Params_ErrorSpans) Reset()      { *m = Params_ErrorSpans{} }
+func (*Params_ErrorSpans) ProtoMessage() {}
+func (*Params_ErrorSpans) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 11}
+}
+func (m *Params_ErrorSpans) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_ErrorSpans) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_ErrorSpans.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params_ErrorSpans) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_ErrorSpans.Merge(m, src)
+}
+func (m *Params_ErrorSpans) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_ErrorSpans) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_ErrorSpans.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_ErrorSpans proto.InternalMessageInfo
+
+func (m *Params_ErrorSpans) GetClientErrors() bool {
+	if m != nil {
+		return m.ClientErrors
+	}
+	return false
+}
+
+func (m *Params_ErrorSpans) GetGrpcStatusTag() string {
+	if m != nil {
+		return m.GrpcStatusTag
+	}
+	return ""
+}
+
+func (m *Params_ErrorSpans) GetResponseFlags() []string {
+	if m != nil {
+		return m.ResponseFlags
+	}
+	return nil
+}
+
+func (m *Params_ErrorSpans) GetResponseFlagsTag() string {
+	if m != nil {
+		return m.ResponseFlagsTag
+	}
+	return ""
+}
+
+func (m *Params_ErrorSpans) GetMessageTag() string {
+	if m != nil {
+		return m.MessageTag
+	}
+	return ""
+}
+
+// Describes how the trace and span IDs of spans are validated and
+// canonicalized to lower case hex IDs joining with New Relic agent
+// traces.
+type Params_IdNormalization struct {
+	// Optional. The format of the IDs.
+	Format Params_IdNormalization_Format `protobuf:"varint,1,opt,name=format,proto3,enum=adapter.newrelic.config.Params_IdNormalization_Format" json:"format,omitempty"`
+	// Optional. The length of trace IDs in hex digits, either 16 or 32.
+	// Shorter trace IDs are left padded with zeros and longer ones are
+	// truncated to their rightmost digits.
+	//
+	// If unspecified, trace IDs are left padded to 16 or, if longer, 32
+	// digits. Span IDs are always left padded to 16 digits.
+	TraceIdLength int32 `protobuf:"varint,2,opt,name=trace_id_length,json=traceIdLength,proto3" json:"trace_id_length,omitempty"`
+}
+
+func (m *Params_IdNormalization) Reset()      { *m = Params_IdNormalization{} }
+func (*Params_IdNormalization) ProtoMessage() {}
+func (*Params_IdNormalization) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 12}
+}
+func (m *Params_IdNormalization) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_IdNormalization) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_IdNormalization.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params_IdNormalization) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_IdNormalization.Merge(m, src)
+}
+func (m *Params_IdNormalization) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_IdNormalization) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_IdNormalization.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_IdNormalization proto.InternalMessageInfo
+
+func (m *Params_IdNormalization) GetFormat() Params_IdNormalization_Format {
+	if m != nil {
+		return m.Format
+	}
+	return AUTO
+}
+
+func (m *Params_IdNormalization) GetTraceIdLength() int32 {
+	if m != nil {
+		return m.TraceIdLength
+	}
+	return 0
+}
+
+// Describes a phase of a request, like DNS resolution, connecting or
+// waiting for the first byte of the response, sent as a child span of
+// the span of the request.
+//
+// The start and end of the phase are read from span tags. Timestamp tags
+// are the time the phase starts or ends at. Duration and numeric tags,
+// in milliseconds like Envoy timing values, are offsets from the start of
+// the request.
+type Params_SpanPhase struct {
+	// Required. The name of the phase, e.g. `connect`, sent as the name of
+	// the phase span and its `phase` attribute.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Optional. The span tag holding the start of the phase.
+	//
+	// If unspecified, the phase starts with the request.
+	StartTag string `protobuf:"bytes,2,opt,name=start_tag,json=startTag,proto3" json:"start_tag,omitempty"`
+	// Optional. The span tag holding the end of the phase.
+	//
+	// If unspecified, the phase ends with the request.
+	EndTag string `protobuf:"bytes,3,opt,name=end_tag,json=endTag,proto3" json:"end_tag,omitempty"`
+}
+
+func (m *Params_SpanPhase) Reset()      { *m = Params_SpanPhase{} }
+func (*Params_SpanPhase) ProtoMessage() {}
+func (*Params_SpanPhase) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cc332a44e926b360, []int{0, 13}
+}
+func (m *Params_SpanPhase) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params_SpanPhase) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params_SpanPhase.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params_SpanPhase) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params_SpanPhase.Merge(m, src)
+}
+func (m *Params_SpanPhase) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params_SpanPhase) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params_SpanPhase.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params_SpanPhase proto.InternalMessageInfo
+
+func (m *Params_SpanPhase) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Params_SpanPhase) GetStartTag() string {
+	if m != nil {
+		return m.StartTag
+	}
+	return ""
+}
+
+func (m *Params_SpanPhase) GetEndTag() string {
+	if m != nil {
+		return m.EndTag
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("adapter.newrelic.config.Params_Region", Params_Region_name, Params_Region_value)
+	proto.RegisterEnum("adapter.newrelic.config.Params_MetricInfo_Type", Params_MetricInfo_Type_name, Params_MetricInfo_Type_value)
+	proto.RegisterEnum("adapter.newrelic.config.Params_IdNormalization_Format", Params_IdNormalization_Format_name, Params_IdNormalization_Format_value)
+	proto.RegisterType((*Params)(nil), "adapter.newrelic.config.Params")
+	proto.RegisterMapType((map[string]*Params_LogInfo)(nil), "adapter.newrelic.config.Params.LogsEntry")
+	proto.RegisterMapType((map[string]*Params_MetricInfo)(nil), "adapter.newrelic.config.Params.MetricsEntry")
+	proto.RegisterMapType((map[string]*Params_SpanRateLimit)(nil), "adapter.newrelic.config.Params.ServiceSpanRateLimitsEntry")
+	proto.RegisterType((*Params_MetricInfo)(nil), "adapter.newrelic.config.Params.MetricInfo")
+	proto.RegisterType((*Params_MetricInfo_ExponentialBuckets)(nil), "adapter.newrelic.config.Params.MetricInfo.ExponentialBuckets")
+	proto.RegisterType((*Params_LogInfo)(nil), "adapter.newrelic.config.Params.LogInfo")
+	proto.RegisterMapType((map[string]string)(nil), "adapter.newrelic.config.Params.LogInfo.SeverityLevelsEntry")
+	proto.RegisterType((*Params_ApiKeySecret)(nil), "adapter.newrelic.config.Params.ApiKeySecret")
+	proto.RegisterType((*Params_TraceSampling)(nil), "adapter.newrelic.config.Params.TraceSampling")
+	proto.RegisterType((*Params_TraceSampling_TailSampling)(nil), "adapter.newrelic.config.Params.TraceSampling.TailSampling")
+	proto.RegisterType((*Params_SpanRateLimit)(nil), "adapter.newrelic.config.Params.SpanRateLimit")
+	proto.RegisterType((*Params_SpanMetrics)(nil), "adapter.newrelic.config.Params.SpanMetrics")
+	proto.RegisterType((*Params_Traces)(nil), "adapter.newrelic.config.Params.Traces")
+	proto.RegisterMapType((map[string]string)(nil), "adapter.newrelic.config.Params.Traces.RenameAttributesEntry")
+	proto.RegisterType((*Params_ServiceName)(nil), "adapter.newrelic.config.Params.ServiceName")
+	proto.RegisterType((*Params_ErrorSpans)(nil), "adapter.newrelic.config.Params.ErrorSpans")
+	proto.RegisterType((*Params_IdNormalization)(nil), "adapter.newrelic.config.Params.IdNormalization")
+	proto.RegisterType((*Params_SpanPhase)(nil), "adapter.newrelic.config.Params.SpanPhase")
+}
+
+func init() { proto.RegisterFile("config/config.proto", fileDescriptor_cc332a44e926b360) }
+
+var fileDescriptor_cc332a44e926b360 = []byte{
+	// 1643 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xcd, 0x72, 0x1b, 0xc7,
+	0x11, 0xc6, 0xe2, 0x8f, 0x44, 0x83, 0x20, 0x56, 0x43, 0x49, 0xde, 0xc0, 0x09, 0xc4, 0x28, 0x15,
+	0x99, 0x8e, 0x6d, 0x30, 0x45, 0xa7, 0x52, 0x2e, 0xc9, 0x4a, 0x05, 0xa4, 0x21, 0x0a, 0x31, 0x09,
+	0xb2, 0x06, 0x40, 0x95, 0xe3, 0xcb, 0x66, 0xb8, 0x18, 0x2e, 0xb7, 0xb4, 0x98, 0xdd, 0xda, 0x19,
+	0xf0, 0x27, 0xa7, 0x3c, 0x42, 0x8e, 0x79, 0x84, 0xbc, 0x40, 0x6e, 0x39, 0x27, 0xae, 0x5c, 0xa2,
+	0xa3, 0x2f, 0xf9, 0x11, 0x75, 0xc9, 0xd1, 0x8f, 0x90, 0x9a, 0x9e, 0x5d, 0x02, 0x90, 0xa8, 0x10,
+	0x3c, 0xe4, 0xb4, 0xd3, 0x5f, 0xff, 0x4c, 0x4f, 0x4f, 0x4f, 0x77, 0x2f, 0xac, 0x79, 0x91, 0x38,
+	0x0e, 0xfc, 0x4d, 0xf3, 0x69, 0xc5, 0x49, 0xa4, 0x22, 0xf2, 0x1e, 0x1b, 0xb1, 0x58, 0xf1, 0xa4,
+	0x25, 0xf8, 0x59, 0xc2, 0xc3, 0xc0, 0x6b, 0x19, 0x76, 0xe3, 0xae, 0x1f, 0xf9, 0x11, 0xca, 0x6c,
+	0xea, 0x95, 0x11, 0x6f, 0x34, 0xfd, 0x28, 0xf2, 0x43, 0xbe, 0x89, 0xd4, 0xd1, 0xe4, 0x78, 0x73,
+	0x34, 0x49, 0x98, 0x0a, 0x22, 0x61, 0xf8, 0x0f, 0xff, 0xd4, 0x84, 0xf2, 0x21, 0x4b, 0xd8, 0x58,
+	0x92, 0xef, 0x43, 0x45, 0xb0, 0x31, 0x97, 0x31, 0xf3, 0xb8, 0x63, 0xad, 0x5b, 0x1b, 0x15, 0x3a,
+	0x05, 0xc8, 0x33, 0x58, 0x1a, 0x73, 0x95, 0x04, 0x9e, 0x74, 0xf2, 0xeb, 0x85, 0x8d, 0xea, 0xd6,
+	0xc7, 0xad, 0x77, 0x78, 0xd2, 0x32, 0xf6, 0x5a, 0xfb, 0x46, 0xbc, 0x23, 0x54, 0x72, 0x41, 0x33,
+	0x65, 0xf2, 0x14, 0x8a, 0x61, 0xe4, 0x4b, 0xa7, 0x80, 0x46, 0x3e, 0xbc, 0xc9, 0xc8, 0x5e, 0xe4,
+	0xa7, 0x16, 0x50, 0x8d, 0x7c, 0x06, 0x8e, 0xb1, 0xe4, 0x7a, 0x2c, 0x19, 0x05, 0x82, 0x85, 0x81,
+	0xba, 0x70, 0xc3, 0x60, 0x1c, 0x28, 0xa7, 0xb8, 0x6e, 0x6d, 0x94, 0xe8, 0x7d, 0xc3, 0xdf, 0x99,
+	0xb2, 0xf7, 0x34, 0x97, 0x50, 0x58, 0x65, 0x71, 0xe0, 0xbe, 0xe0, 0x17, 0xae, 0xe4, 0x5e, 0xc2,
+	0x95, 0x53, 0x5a, 0xb7, 0x16, 0x39, 0x47, 0x3b, 0x0e, 0xbe, 0xe4, 0x17, 0x7d, 0xd4, 0xa1, 0x2b,
+	0x6c, 0x86, 0x22, 0x3f, 0x00, 0x60, 0x9e, 0x17, 0x4d, 0x84, 0x72, 0x83, 0x91, 0x73, 0xd7, 0xc4,
+	0x2c, 0x45, 0xba, 0x23, 0xf2, 0x0b, 0x28, 0x27, 0xdc, 0x0f, 0x22, 0xe1, 0x94, 0xd7, 0xad, 0x8d,
+	0xd5, 0xad, 0x47, 0x37, 0x6d, 0x45, 0x51, 0x9a, 0xa6, 0x5a, 0xe4, 0x87, 0xb0, 0x92, 0x86, 0xcd,
+	0x3d, 0x89, 0xa4, 0x72, 0x96, 0x70, 0x83, 0x6a, 0x8a, 0x3d, 0x8f, 0x24, 0x7a, 0x20, 0x63, 0x26,
+	0x52, 0x81, 0x65, 0xe3, 0x01, 0x22, 0xc8, 0x7e, 0x1f, 0x2a, 0x3a, 0x6c, 0x86, 0x5b, 0x41, 0xee,
+	0xb2, 0x06, 0x90, 0xf9, 0x00, 0xaa, 0xfc, 0x94, 0x0b, 0x95, 0xb2, 0x01, 0xd9, 0x60, 0x20, 0x14,
+	0x18, 0xc0, 0xaa, 0x4a, 0x98, 0xc7, 0x5d, 0xc9, 0xc6, 0x71, 0x18, 0x08, 0xdf, 0xa9, 0x62, 0xc8,
+	0x3e, 0xb9, 0xe9, 0x1c, 0x03, 0xad, 0xd5, 0x4f, 0x95, 0x68, 0x4d, 0xcd, 0x92, 0x64, 0x08, 0x75,
+	0xed, 0xa0, 0x9b, 0x30, 0xc5, 0xd3, 0x9b, 0x5b, 0x59, 0xcc, 0x6c, 0x3f, 0x66, 0x82, 0x32, 0xc5,
+	0xf1, 0x42, 0x69, 0x4d, 0xce, 0x92, 0x44, 0x82, 0x23, 0x79, 0x72, 0x1a, 0x68, 0x77, 0xe7, 0xcd,
+	0x4b, 0xa7, 0x86, 0xc9, 0xf6, 0xf8, 0x46, 0xfb, 0x46, 0x7f, 0x6e, 0x9b, 0x34, 0xfb, 0xee, 0xc9,
+	0xeb, 0x78, 0xa4, 0x07, 0x2b, 0xb8, 0x59, 0xf6, 0x34, 0x56, 0xf1, 0x20, 0x1f, 0x2d, 0x72, 0x90,
+	0xf4, 0x79, 0xd0, 0xaa, 0x9c, 0x12, 0x3a, 0x63, 0x30, 0x58, 0xd2, 0xa9, 0xa3, 0xa5, 0x47, 0x0b,
+	0x45, 0x5a, 0xd2, 0x54, 0x0b, 0xfd, 0x49, 0x83, 0xa0, 0x9f, 0xae, 0x63, 0x2f, 0xe8, 0x8f, 0xd1,
+	0xe9, 0xb1, 0x31, 0xa7, 0x55, 0x39, 0x25, 0xc8, 0x97, 0x50, 0xe5, 0x49, 0x12, 0x25, 0x18, 0x52,
+	0xe9, 0xdc, 0x41, 0x73, 0x3f, 0xb9, 0xc9, 0x5c, 0x47, 0xab, 0xe8, 0x33, 0x4a, 0x0a, 0xfc, 0x6a,
+	0x4d, 0xbe, 0x06, 0x3b, 0x18, 0xb9, 0x22, 0x4a, 0xc6, 0x2c, 0x0c, 0x7e, 0x8b, 0x55, 0xc8, 0x21,
+	0x68, 0x71, 0xf3, 0x26, 0x8b, 0xdd, 0x51, 0x6f, 0x56, 0x8d, 0xd6, 0x83, 0x79, 0x80, 0xfc, 0x0a,
+	0x30, 0x8e, 0x6e, 0x7c, 0xc2, 0x24, 0x97, 0xce, 0xda, 0x62, 0xd5, 0x45, 0xfb, 0x75, 0xa8, 0x35,
+	0x28, 0xc8, 0x6c, 0x29, 0x1b, 0x2f, 0x8b, 0x00, 0xe6, 0x42, 0xba, 0xe2, 0x38, 0x22, 0x04, 0x8a,
+	0x18, 0x4b, 0x53, 0x12, 0x71, 0x4d, 0x76, 0xa0, 0xa8, 0x2e, 0x62, 0xee, 0xe4, 0xf1, 0x5d, 0x6f,
+	0x2e, 0x56, 0x0a, 0xb5, 0xb5, 0xd6, 0xe0, 0x22, 0xe6, 0x14, 0x95, 0xc9, 0x27, 0x40, 0x02, 0xe1,
+	0x85, 0x93, 0x11, 0x77, 0x99, 0x52, 0x49, 0x70, 0x34, 0x51, 0xdc, 0x14, 0xc6, 0x0a, 0xbd, 0x93,
+	0x72, 0xda, 0x57, 0x0c, 0x2d, 0xce, 0xcf, 0xdf, 0x12, 0x2f, 0x1a, 0x71, 0x7e, 0xfe, 0xa6, 0xb8,
+	0x03, 0x4b, 0x47, 0x13, 0xef, 0x05, 0x57, 0xd2, 0x29, 0xad, 0x17, 0x36, 0x2c, 0x9a, 0x91, 0x44,
+	0xc0, 0x1a, 0x3f, 0x8f, 0x23, 0xc1, 0x85, 0x0a, 0x58, 0xe8, 0x66, 0x52, 0x65, 0xbc, 0x8a, 0xa7,
+	0xb7, 0x38, 0x4b, 0x67, 0x6a, 0x65, 0xdb, 0x18, 0xa1, 0x84, 0xbf, 0x85, 0x91, 0x75, 0xa8, 0xc6,
+	0x3c, 0xf1, 0x34, 0x18, 0x72, 0xe9, 0x2c, 0xa1, 0x37, 0xb3, 0x10, 0x79, 0x04, 0x75, 0xf9, 0x82,
+	0x2b, 0xef, 0xc4, 0x1d, 0xb3, 0x73, 0xf7, 0x28, 0x10, 0x12, 0x4b, 0x59, 0x89, 0xd6, 0x0c, 0xbc,
+	0xcf, 0xce, 0xb7, 0x03, 0x21, 0xf5, 0x55, 0x4c, 0x44, 0x90, 0x55, 0x32, 0x5c, 0x37, 0xbe, 0x02,
+	0xf2, 0xb6, 0x1f, 0xe4, 0x2e, 0x94, 0xb0, 0x0a, 0xe3, 0xad, 0x95, 0xa8, 0x21, 0x34, 0x2a, 0x15,
+	0x4b, 0x14, 0xde, 0x9b, 0x45, 0x0d, 0x41, 0xee, 0x43, 0xf9, 0x98, 0x79, 0x2a, 0x4a, 0x9c, 0x02,
+	0xc2, 0x29, 0xf5, 0xb0, 0x0b, 0x45, 0x7d, 0x5b, 0xa4, 0x0e, 0xd5, 0x61, 0xaf, 0x7f, 0xd8, 0xd9,
+	0xe9, 0x3e, 0xeb, 0x76, 0xbe, 0xb0, 0x73, 0xa4, 0x02, 0xa5, 0xdd, 0xf6, 0x70, 0xb7, 0x63, 0x5b,
+	0x7a, 0xb9, 0x73, 0x30, 0xec, 0x0d, 0xec, 0x3c, 0xa9, 0xc2, 0x52, 0x7f, 0xb8, 0xbf, 0xdf, 0xa6,
+	0xbf, 0xb6, 0x0b, 0xa4, 0x06, 0x95, 0xe7, 0xdd, 0xfe, 0xe0, 0x60, 0x97, 0xb6, 0xf7, 0xed, 0x62,
+	0xe3, 0x18, 0x56, 0x66, 0xdb, 0x21, 0xb1, 0xa1, 0xf0, 0x82, 0x5f, 0xa4, 0x29, 0xa5, 0x97, 0xe4,
+	0x97, 0x50, 0x3a, 0x65, 0xe1, 0xc4, 0xa4, 0xd4, 0x02, 0x6f, 0x6c, 0x7a, 0x0d, 0xd4, 0x28, 0x3e,
+	0xce, 0x7f, 0x66, 0x35, 0xfe, 0x61, 0xc1, 0xd2, 0x5e, 0xe4, 0x6b, 0x98, 0x7c, 0x08, 0xf6, 0x98,
+	0x4b, 0xc9, 0x7c, 0xee, 0x2a, 0x3e, 0x8e, 0x43, 0xa6, 0xb2, 0x1c, 0xae, 0xa7, 0xf8, 0x20, 0x85,
+	0xc9, 0x08, 0xea, 0x92, 0x9f, 0xf2, 0x04, 0x7b, 0x29, 0x3f, 0xe5, 0x61, 0xd6, 0xe4, 0x9f, 0x2c,
+	0xd0, 0x9f, 0x31, 0x15, 0xfa, 0xa9, 0xfa, 0x1e, 0x6a, 0x9b, 0x9a, 0xb9, 0x2a, 0xe7, 0xc0, 0x46,
+	0x1b, 0xd6, 0xae, 0x11, 0xbb, 0x26, 0x16, 0x77, 0x67, 0x63, 0x51, 0x99, 0x3d, 0xdf, 0x6f, 0xa0,
+	0x72, 0x35, 0x11, 0x5c, 0xa3, 0xf8, 0x74, 0x3e, 0x88, 0x1f, 0x2c, 0xe8, 0xfd, 0xec, 0x0e, 0x3f,
+	0x83, 0x95, 0xd9, 0x86, 0xaf, 0x37, 0xe1, 0xe2, 0x34, 0xdb, 0x84, 0x8b, 0x53, 0x9d, 0x84, 0xc7,
+	0x41, 0x98, 0x39, 0x87, 0xeb, 0xc6, 0x5f, 0xf3, 0x50, 0x9b, 0x6b, 0x7a, 0xe4, 0x03, 0xa8, 0x9f,
+	0x70, 0x36, 0x72, 0xd3, 0x34, 0x67, 0xbe, 0x09, 0xbe, 0x45, 0x57, 0x35, 0x7c, 0x78, 0x85, 0x92,
+	0x1e, 0x14, 0x15, 0x0b, 0xc2, 0xd4, 0xe5, 0xc7, 0xb7, 0x6a, 0xad, 0xad, 0x01, 0x0b, 0xc2, 0x8c,
+	0xa0, 0x68, 0xa7, 0xf1, 0x67, 0x0b, 0x56, 0x66, 0x61, 0xf2, 0x04, 0xca, 0x67, 0x81, 0x18, 0x45,
+	0x67, 0xe8, 0x40, 0x75, 0xeb, 0x7b, 0x2d, 0x33, 0x13, 0xb6, 0xb2, 0x99, 0xb0, 0xf5, 0x45, 0x3a,
+	0x13, 0x6e, 0x2f, 0x7f, 0xf3, 0xcf, 0x07, 0xb9, 0x3f, 0xfc, 0xeb, 0x81, 0x45, 0x53, 0x15, 0x72,
+	0x08, 0x77, 0x74, 0x86, 0x08, 0xef, 0xc2, 0x55, 0x27, 0x09, 0x97, 0x27, 0x51, 0x38, 0x72, 0xf2,
+	0x8b, 0xdb, 0xb1, 0x53, 0xed, 0x41, 0xa6, 0xac, 0x27, 0x16, 0xfd, 0xc8, 0xd3, 0x36, 0x57, 0xc0,
+	0xe7, 0x59, 0x19, 0xb3, 0x73, 0xd3, 0xc9, 0x1a, 0x07, 0x50, 0x9b, 0xeb, 0xb1, 0x64, 0x03, 0x6c,
+	0x33, 0xe1, 0xc4, 0x3c, 0xd1, 0x93, 0x5b, 0x24, 0x46, 0x59, 0x24, 0x11, 0x3f, 0xe4, 0x49, 0x1f,
+	0x51, 0x9d, 0x36, 0x47, 0x93, 0x44, 0x9a, 0xd7, 0x5d, 0xa2, 0x86, 0x68, 0x9c, 0x41, 0xe3, 0xdd,
+	0x7d, 0xfd, 0x9a, 0x1c, 0xda, 0x99, 0xcf, 0xa1, 0x5b, 0x0e, 0x25, 0x33, 0x99, 0xf4, 0x11, 0x54,
+	0x67, 0xfa, 0xfc, 0xff, 0x1e, 0xaf, 0x1b, 0x7f, 0xcb, 0x43, 0xd9, 0x44, 0x80, 0x6c, 0xc1, 0x3d,
+	0xc1, 0xcf, 0x5c, 0xdc, 0xcb, 0xf5, 0x22, 0xa1, 0xe7, 0xb1, 0x20, 0x12, 0x12, 0x95, 0x96, 0xe9,
+	0x9a, 0xe0, 0x67, 0x54, 0xf3, 0x76, 0xa6, 0x2c, 0x12, 0xc0, 0x9d, 0x84, 0x6b, 0x6b, 0xb3, 0xad,
+	0xc1, 0x3c, 0xe1, 0xcf, 0x17, 0x1b, 0x21, 0x5a, 0x14, 0xf5, 0xa7, 0x0d, 0xc4, 0xbc, 0x61, 0x3b,
+	0x79, 0x03, 0xfe, 0xff, 0x76, 0xad, 0xc6, 0x0e, 0xdc, 0xbb, 0xd6, 0x91, 0x5b, 0x55, 0x89, 0xa7,
+	0x50, 0x9d, 0x99, 0x68, 0x74, 0x7d, 0xd7, 0x33, 0x0d, 0x4f, 0x1c, 0x0b, 0xb7, 0x4d, 0x29, 0x8d,
+	0x7b, 0x61, 0xc0, 0x85, 0xc2, 0x48, 0x55, 0x68, 0x4a, 0x35, 0xfe, 0x6e, 0x01, 0x4c, 0x47, 0x18,
+	0xf2, 0x23, 0xa8, 0x19, 0x86, 0x8b, 0xb3, 0x4c, 0x76, 0x0f, 0x2b, 0x06, 0x44, 0x41, 0xec, 0x60,
+	0x7e, 0x12, 0x7b, 0xae, 0x54, 0x4c, 0x4d, 0xa4, 0xab, 0x98, 0x9f, 0xba, 0x55, 0xd3, 0x70, 0x1f,
+	0xd1, 0x01, 0xf3, 0xc9, 0x8f, 0x61, 0x35, 0xe1, 0x32, 0x8e, 0x84, 0xe4, 0xee, 0x71, 0xc8, 0xfc,
+	0x2c, 0x72, 0xb5, 0x0c, 0x7d, 0xa6, 0x41, 0xf2, 0x31, 0x90, 0x79, 0x31, 0xb4, 0x58, 0x44, 0x8b,
+	0xf6, 0x9c, 0xa8, 0x36, 0xfa, 0x00, 0xaa, 0x57, 0x95, 0x9e, 0xf9, 0xf8, 0x5f, 0x53, 0xa1, 0x90,
+	0x15, 0x79, 0xe6, 0x37, 0xfe, 0x62, 0x41, 0xfd, 0x8d, 0x11, 0x8a, 0xf4, 0xa0, 0x7c, 0xac, 0x01,
+	0xd3, 0x22, 0x57, 0xb7, 0x7e, 0x7e, 0xcb, 0x19, 0xac, 0xf5, 0x0c, 0xb5, 0x69, 0x6a, 0x45, 0x47,
+	0xc0, 0xfc, 0x2c, 0x04, 0x23, 0x37, 0xe4, 0xc2, 0x57, 0x27, 0xe9, 0x3b, 0x34, 0xe3, 0x7f, 0x77,
+	0xb4, 0x87, 0xe0, 0xc3, 0x27, 0x50, 0x36, 0x9a, 0x64, 0x19, 0x8a, 0xed, 0xe1, 0xe0, 0xc0, 0xce,
+	0x91, 0x25, 0x28, 0x3c, 0xef, 0x7c, 0x65, 0x5b, 0xba, 0x6d, 0x6e, 0x7f, 0xea, 0xf6, 0xbb, 0xbd,
+	0xdd, 0xbd, 0x8e, 0x9d, 0xd7, 0x9d, 0x77, 0x40, 0xdb, 0x3b, 0x9d, 0xc3, 0x36, 0xed, 0xf4, 0x06,
+	0x76, 0xa1, 0x31, 0x84, 0xca, 0xd5, 0xcc, 0x76, 0xed, 0x60, 0xf6, 0x3e, 0x54, 0xb0, 0xa9, 0xcf,
+	0xdc, 0xc0, 0x32, 0x02, 0x3a, 0x4e, 0xef, 0xc1, 0x12, 0x17, 0x23, 0x64, 0x15, 0x90, 0x55, 0xe6,
+	0x62, 0x34, 0x60, 0xfe, 0xc3, 0x9f, 0x42, 0xd9, 0xfc, 0x7a, 0x91, 0xfb, 0x40, 0x68, 0x67, 0xb7,
+	0x7b, 0xd0, 0x73, 0xe7, 0x5b, 0x7e, 0x19, 0xf2, 0xc3, 0xbe, 0x6d, 0xe9, 0x6f, 0x67, 0x68, 0xe7,
+	0xb7, 0x3f, 0x7f, 0xf9, 0xaa, 0x99, 0xfb, 0xf6, 0x55, 0x33, 0xf7, 0xdd, 0xab, 0xa6, 0xf5, 0xbb,
+	0xcb, 0xa6, 0xf5, 0xc7, 0xcb, 0xa6, 0xf5, 0xcd, 0x65, 0xd3, 0x7a, 0x79, 0xd9, 0xb4, 0xfe, 0x7d,
+	0xd9, 0xb4, 0xfe, 0x73, 0xd9, 0xcc, 0x7d, 0x77, 0xd9, 0xb4, 0x7e, 0xff, 0xba, 0x99, 0x7b, 0xf9,
+	0xba, 0x99, 0xfb, 0xf6, 0x75, 0x33, 0xf7, 0x75, 0xd9, 0x44, 0xf4, 0xa8, 0x8c, 0x25, 0xf3, 0xd3,
+	0xff, 0x0e, 0x00, 0xa3, 0x36, 0xa6, 0xaf, 0xe2, 0x0f, 0x00, 0x00,
+}
+
+func (x Params_Region) String() string {
+	s, ok := Params_Region_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Params_MetricInfo_Type) String() string {
+	s, ok := Params_MetricInfo_Type_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Params_IdNormalization_Format) String() string {
+	s, ok := Params_IdNormalization_Format_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (this *Params) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params)
+	if !ok {
+		that2, ok := that.(Params)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Namespace != that1.Namespace {
+		return false
+	}
+	if len(this.Metrics) != len(that1.Metrics) {
+		return false
+	}
+	for i := range this.Metrics {
+		if !this.Metrics[i].Equal(that1.Metrics[i]) {
+			return false
+		}
+	}
+	if len(this.Logs) != len(that1.Logs) {
+		return false
+	}
+	for i := range this.Logs {
+		if !this.Logs[i].Equal(that1.Logs[i]) {
+			return false
+		}
+	}
+	if this.MetricCardinalityLimit != that1.MetricCardinalityLimit {
+		return false
+	}
+	if !this.ApiKeySecret.Equal(that1.ApiKeySecret) {
+		return false
+	}
+	if this.AccountId != that1.AccountId {
+		return false
+	}
+	if this.Region != that1.Region {
+		return false
+	}
+	if this.MetricsHost != that1.MetricsHost {
+		return false
+	}
+	if this.SpansHost != that1.SpansHost {
+		return false
+	}
+	if this.LogsHost != that1.LogsHost {
+		return false
+	}
+	if this.EventsHost != that1.EventsHost {
+		return false
+	}
+	if !this.TraceSampling.Equal(that1.TraceSampling) {
+		return false
+	}
+	if !this.SpanRateLimit.Equal(that1.SpanRateLimit) {
+		return false
+	}
+	if len(this.ServiceSpanRateLimits) != len(that1.ServiceSpanRateLimits) {
+		return false
+	}
+	for i := range this.ServiceSpanRateLimits {
+		if !this.ServiceSpanRateLimits[i].Equal(that1.ServiceSpanRateLimits[i]) {
+			return false
+		}
+	}
+	if !this.SpanMetrics.Equal(that1.SpanMetrics) {
+		return false
+	}
+	if !this.Traces.Equal(that1.Traces) {
+		return false
+	}
+	if !this.ServiceName.Equal(that1.ServiceName) {
+		return false
+	}
+	if !this.ErrorSpans.Equal(that1.ErrorSpans) {
+		return false
+	}
+	if !this.IdNormalization.Equal(that1.IdNormalization) {
+		return false
+	}
+	if len(this.SpanPhases) != len(that1.SpanPhases) {
+		return false
+	}
+	for i := range this.SpanPhases {
+		if !this.SpanPhases[i].Equal(that1.SpanPhases[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Params_MetricInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_MetricInfo)
+	if !ok {
+		that2, ok := that.(Params_MetricInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if len(this.IncludeAttributes) != len(that1.IncludeAttributes) {
+		return false
+	}
+	for i := range this.IncludeAttributes {
+		if this.IncludeAttributes[i] != that1.IncludeAttributes[i] {
+			return false
+		}
+	}
+	if len(this.ExcludeAttributes) != len(that1.ExcludeAttributes) {
+		return false
+	}
+	for i := range this.ExcludeAttributes {
+		if this.ExcludeAttributes[i] != that1.ExcludeAttributes[i] {
+			return false
+		}
+	}
+	if len(this.Buckets) != len(that1.Buckets) {
+		return false
+	}
+	for i := range this.Buckets {
+		if this.Buckets[i] != that1.Buckets[i] {
+			return false
+		}
+	}
+	if !this.ExponentialBuckets.Equal(that1.ExponentialBuckets) {
+		return false
+	}
+	if len(this.Percentiles) != len(that1.Percentiles) {
+		return false
+	}
+	for i := range this.Percentiles {
+		if this.Percentiles[i] != that1.Percentiles[i] {
+			return false
+		}
+	}
+	if this.SketchMaxBins != that1.SketchMaxBins {
+		return false
+	}
+	if this.Unit != that1.Unit {
+		return false
+	}
+	return true
+}
+func (this *Params_MetricInfo_ExponentialBuckets) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_MetricInfo_ExponentialBuckets)
+	if !ok {
+		that2, ok := that.(Params_MetricInfo_ExponentialBuckets)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Count != that1.Count {
+		return false
+	}
+	if this.Start != that1.Start {
+		return false
+	}
+	if this.Factor != that1.Factor {
+		return false
+	}
+	return true
+}
+func (this *Params_LogInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_LogInfo)
+	if !ok {
+		that2, ok := that.(Params_LogInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.MessageTemplate != that1.MessageTemplate {
+		return false
+	}
+	if len(this.SeverityLevels) != len(that1.SeverityLevels) {
+		return false
+	}
+	for i := range this.SeverityLevels {
+		if this.SeverityLevels[i] != that1.SeverityLevels[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *Params_ApiKeySecret) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_ApiKeySecret)
+	if !ok {
+		that2, ok := that.(Params_ApiKeySecret)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Env != that1.Env {
+		return false
+	}
+	if this.File != that1.File {
+		return false
+	}
+	return true
+}
+func (this *Params_TraceSampling) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_TraceSampling)
+	if !ok {
+		that2, ok := that.(Params_TraceSampling)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.HeadPercentage != that1.HeadPercentage {
+		return false
+	}
+	if !this.Tail.Equal(that1.Tail) {
+		return false
+	}
+	return true
+}
+func (this *Params_TraceSampling_TailSampling) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_TraceSampling_TailSampling)
+	if !ok {
+		that2, ok := that.(Params_TraceSampling_TailSampling)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Window != that1.Window {
+		return false
+	}
+	if this.LatencyThreshold != that1.LatencyThreshold {
+		return false
+	}
+	if this.MaxTraces != that1.MaxTraces {
+		return false
+	}
+	return true
+}
+func (this *Params_SpanRateLimit) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_SpanRateLimit)
+	if !ok {
+		that2, ok := that.(Params_SpanRateLimit)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.SpansPerSecond != that1.SpansPerSecond {
+		return false
+	}
+	if this.Burst != that1.Burst {
+		return false
+	}
+	return true
+}
+func (this *Params_SpanMetrics) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_SpanMetrics)
+	if !ok {
+		that2, ok := that.(Params_SpanMetrics)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Namespace != that1.Namespace {
+		return false
+	}
+	return true
+}
+func (this *Params_Traces) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_Traces)
+	if !ok {
+		that2, ok := that.(Params_Traces)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.NewRelicConventions != that1.NewRelicConventions {
+		return false
+	}
+	if len(this.RenameAttributes) != len(that1.RenameAttributes) {
+		return false
+	}
+	for i := range this.RenameAttributes {
+		if this.RenameAttributes[i] != that1.RenameAttributes[i] {
+			return false
+		}
+	}
+	if len(this.IncludeAttributes) != len(that1.IncludeAttributes) {
+		return false
+	}
+	for i := range this.IncludeAttributes {
+		if this.IncludeAttributes[i] != that1.IncludeAttributes[i] {
+			return false
+		}
+	}
+	if len(this.ExcludeAttributes) != len(that1.ExcludeAttributes) {
+		return false
+	}
+	for i := range this.ExcludeAttributes {
+		if this.ExcludeAttributes[i] != that1.ExcludeAttributes[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *Params_ServiceName) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_ServiceName)
+	if !ok {
+		that2, ok := that.(Params_ServiceName)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Server) != len(that1.Server) {
+		return false
+	}
+	for i := range this.Server {
+		if this.Server[i] != that1.Server[i] {
+			return false
+		}
+	}
+	if len(this.Client) != len(that1.Client) {
+		return false
+	}
+	for i := range this.Client {
+		if this.Client[i] != that1.Client[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *Params_ErrorSpans) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_ErrorSpans)
+	if !ok {
+		that2, ok := that.(Params_ErrorSpans)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ClientErrors != that1.ClientErrors {
+		return false
+	}
+	if this.GrpcStatusTag != that1.GrpcStatusTag {
+		return false
+	}
+	if len(this.ResponseFlags) != len(that1.ResponseFlags) {
+		return false
+	}
+	for i := range this.ResponseFlags {
+		if this.ResponseFlags[i] != that1.ResponseFlags[i] {
+			return false
+		}
+	}
+	if this.ResponseFlagsTag != that1.ResponseFlagsTag {
+		return false
+	}
+	if this.MessageTag != that1.MessageTag {
+		return false
+	}
+	return true
+}
+func (this *Params_IdNormalization) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_IdNormalization)
+	if !ok {
+		that2, ok := that.(Params_IdNormalization)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Format != that1.Format {
+		return false
+	}
+	if this.TraceIdLength != that1.TraceIdLength {
+		return false
+	}
+	return true
+}
+func (this *Params_SpanPhase) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params_SpanPhase)
+	if !ok {
+		that2, ok := that.(Params_SpanPhase)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.StartTag != that1.StartTag {
+		return false
+	}
+	if this.EndTag != that1.EndTag {
+		return false
+	}
+	return true
+}
+func (this *Params) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 24)
+	s = append(s, "&config.Params{")
+	s = append(s, "Namespace: "+fmt.Sprintf("%#v", this.Namespace)+",\n")
+	keysForMetrics := make([]string, 0, len(this.Metrics))
+	for k, _ := range this.Metrics {
+		keysForMetrics = append(keysForMetrics, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForMetrics)
+	mapStringForMetrics := "map[string]*Params_MetricInfo{"
+	for _, k := range keysForMetrics {
+		mapStringForMetrics += fmt.Sprintf("%#v: %#v,", k, this.Metrics[k])
+	}
+	mapStringForMetrics += "}"
+	if this.Metrics != nil {
+		s = append(s, "Metrics: "+mapStringForMetrics+",\n")
+	}
+	keysForLogs := make([]string, 0, len(this.Logs))
+	for k, _ := range this.Logs {
+		keysForLogs = append(keysForLogs, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForLogs)
+	mapStringForLogs := "map[string]*Params_LogInfo{"
+	for _, k := range keysForLogs {
+		mapStringForLogs += fmt.Sprintf("%#v: %#v,", k, this.Logs[k])
+	}
+	mapStringForLogs += "}"
+	if this.Logs != nil {
+		s = append(s, "Logs: "+mapStringForLogs+",\n")
+	}
+	s = append(s, "MetricCardinalityLimit: "+fmt.Sprintf("%#v", this.MetricCardinalityLimit)+",\n")
+	if this.ApiKeySecret != nil {
+		s = append(s, "ApiKeySecret: "+fmt.Sprintf("%#v", this.ApiKeySecret)+",\n")
+	}
+	s = append(s, "AccountId: "+fmt.Sprintf("%#v", this.AccountId)+",\n")
+	s = append(s, "Region: "+fmt.Sprintf("%#v", this.Region)+",\n")
+	s = append(s, "MetricsHost: "+fmt.Sprintf("%#v", this.MetricsHost)+",\n")
+	s = append(s, "SpansHost: "+fmt.Sprintf("%#v", this.SpansHost)+",\n")
+	s = append(s, "LogsHost: "+fmt.Sprintf("%#v", this.LogsHost)+",\n")
+	s = append(s, "EventsHost: "+fmt.Sprintf("%#v", this.EventsHost)+",\n")
+	if this.TraceSampling != nil {
+		s = append(s, "TraceSampling: "+fmt.Sprintf("%#v", this.TraceSampling)+",\n")
+	}
+	if this.SpanRateLimit != nil {
+		s = append(s, "SpanRateLimit: "+fmt.Sprintf("%#v", this.SpanRateLimit)+",\n")
+	}
+	keysForServiceSpanRateLimits := make([]string, 0, len(this.ServiceSpanRateLimits))
+	for k, _ := range this.ServiceSpanRateLimits {
+		keysForServiceSpanRateLimits = append(keysForServiceSpanRateLimits, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForServiceSpanRateLimits)
+	mapStringForServiceSpanRateLimits := "map[string]*Params_SpanRateLimit{"
+	for _, k := range keysForServiceSpanRateLimits {
+		mapStringForServiceSpanRateLimits += fmt.Sprintf("%#v: %#v,", k, this.ServiceSpanRateLimits[k])
+	}
+	mapStringForServiceSpanRateLimits += "}"
+	if this.ServiceSpanRateLimits != nil {
+		s = append(s, "ServiceSpanRateLimits: "+mapStringForServiceSpanRateLimits+",\n")
+	}
+	if this.SpanMetrics != nil {
+		s = append(s, "SpanMetrics: "+fmt.Sprintf("%#v", this.SpanMetrics)+",\n")
+	}
+	if this.Traces != nil {
+		s = append(s, "Traces: "+fmt.Sprintf("%#v", this.Traces)+",\n")
+	}
+	if this.ServiceName != nil {
+		s = append(s, "ServiceName: "+fmt.Sprintf("%#v", this.ServiceName)+",\n")
+	}
+	if this.ErrorSpans != nil {
+		s = append(s, "ErrorSpans: "+fmt.Sprintf("%#v", this.ErrorSpans)+",\n")
+	}
+	if this.IdNormalization != nil {
+		s = append(s, "IdNormalization: "+fmt.Sprintf("%#v", this.IdNormalization)+",\n")
+	}
+	if this.SpanPhases != nil {
+		s = append(s, "SpanPhases: "+fmt.Sprintf("%#v", this.SpanPhases)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_MetricInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 13)
+	s = append(s, "&config.Params_MetricInfo{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "IncludeAttributes: "+fmt.Sprintf("%#v", this.IncludeAttributes)+",\n")
+	s = append(s, "ExcludeAttributes: "+fmt.Sprintf("%#v", this.ExcludeAttributes)+",\n")
+	s = append(s, "Buckets: "+fmt.Sprintf("%#v", this.Buckets)+",\n")
+	if this.ExponentialBuckets != nil {
+		s = append(s, "ExponentialBuckets: "+fmt.Sprintf("%#v", this.ExponentialBuckets)+",\n")
+	}
+	s = append(s, "Percentiles: "+fmt.Sprintf("%#v", this.Percentiles)+",\n")
+	s = append(s, "SketchMaxBins: "+fmt.Sprintf("%#v", this.SketchMaxBins)+",\n")
+	s = append(s, "Unit: "+fmt.Sprintf("%#v", this.Unit)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_MetricInfo_ExponentialBuckets) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&config.Params_MetricInfo_ExponentialBuckets{")
+	s = append(s, "Count: "+fmt.Sprintf("%#v", this.Count)+",\n")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "Factor: "+fmt.Sprintf("%#v", this.Factor)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_LogInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&config.Params_LogInfo{")
+	s = append(s, "MessageTemplate: "+fmt.Sprintf("%#v", this.MessageTemplate)+",\n")
+	keysForSeverityLevels := make([]string, 0, len(this.SeverityLevels))
+	for k, _ := range this.SeverityLevels {
+		keysForSeverityLevels = append(keysForSeverityLevels, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForSeverityLevels)
+	mapStringForSeverityLevels := "map[string]string{"
+	for _, k := range keysForSeverityLevels {
+		mapStringForSeverityLevels += fmt.Sprintf("%#v: %#v,", k, this.SeverityLevels[k])
+	}
+	mapStringForSeverityLevels += "}"
+	if this.SeverityLevels != nil {
+		s = append(s, "SeverityLevels: "+mapStringForSeverityLevels+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_ApiKeySecret) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&config.Params_ApiKeySecret{")
+	s = append(s, "Env: "+fmt.Sprintf("%#v", this.Env)+",\n")
+	s = append(s, "File: "+fmt.Sprintf("%#v", this.File)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_TraceSampling) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&config.Params_TraceSampling{")
+	s = append(s, "HeadPercentage: "+fmt.Sprintf("%#v", this.HeadPercentage)+",\n")
+	if this.Tail != nil {
+		s = append(s, "Tail: "+fmt.Sprintf("%#v", this.Tail)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_TraceSampling_TailSampling) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&config.Params_TraceSampling_TailSampling{")
+	s = append(s, "Window: "+fmt.Sprintf("%#v", this.Window)+",\n")
+	s = append(s, "LatencyThreshold: "+fmt.Sprintf("%#v", this.LatencyThreshold)+",\n")
+	s = append(s, "MaxTraces: "+fmt.Sprintf("%#v", this.MaxTraces)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_SpanRateLimit) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&config.Params_SpanRateLimit{")
+	s = append(s, "SpansPerSecond: "+fmt.Sprintf("%#v", this.SpansPerSecond)+",\n")
+	s = append(s, "Burst: "+fmt.Sprintf("%#v", this.Burst)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_SpanMetrics) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&config.Params_SpanMetrics{")
+	s = append(s, "Namespace: "+fmt.Sprintf("%#v", this.Namespace)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_Traces) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&config.Params_Traces{")
+	s = append(s, "NewRelicConventions: "+fmt.Sprintf("%#v", this.NewRelicConventions)+",\n")
+	keysForRenameAttributes := make([]string, 0, len(this.RenameAttributes))
+	for k, _ := range this.RenameAttributes {
+		keysForRenameAttributes = append(keysForRenameAttributes, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForRenameAttributes)
+	mapStringForRenameAttributes := "map[string]string{"
+	for _, k := range keysForRenameAttributes {
+		mapStringForRenameAttributes += fmt.Sprintf("%#v: %#v,", k, this.RenameAttributes[k])
+	}
+	mapStringForRenameAttributes += "}"
+	if this.RenameAttributes != nil {
+		s = append(s, "RenameAttributes: "+mapStringForRenameAttributes+",\n")
+	}
+	s = append(s, "IncludeAttributes: "+fmt.Sprintf("%#v", this.IncludeAttributes)+",\n")
+	s = append(s, "ExcludeAttributes: "+fmt.Sprintf("%#v", this.ExcludeAttributes)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_ServiceName) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&config.Params_ServiceName{")
+	s = append(s, "Server: "+fmt.Sprintf("%#v", this.Server)+",\n")
+	s = append(s, "Client: "+fmt.Sprintf("%#v", this.Client)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_ErrorSpans) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&config.Params_ErrorSpans{")
+	s = append(s, "ClientErrors: "+fmt.Sprintf("%#v", this.ClientErrors)+",\n")
+	s = append(s, "GrpcStatusTag: "+fmt.Sprintf("%#v", this.GrpcStatusTag)+",\n")
+	s = append(s, "ResponseFlags: "+fmt.Sprintf("%#v", this.ResponseFlags)+",\n")
+	s = append(s, "ResponseFlagsTag: "+fmt.Sprintf("%#v", this.ResponseFlagsTag)+",\n")
+	s = append(s, "MessageTag: "+fmt.Sprintf("%#v", this.MessageTag)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_IdNormalization) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&config.Params_IdNormalization{")
+	s = append(s, "Format: "+fmt.Sprintf("%#v", this.Format)+",\n")
+	s = append(s, "TraceIdLength: "+fmt.Sprintf("%#v", this.TraceIdLength)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Params_SpanPhase) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&config.Params_SpanPhase{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "StartTag: "+fmt.Sprintf("%#v", this.StartTag)+",\n")
+	s = append(s, "EndTag: "+fmt.Sprintf("%#v", this.EndTag)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringConfig(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *Params) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Namespace) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Namespace)))
+		i += copy(dAtA[i:], m.Namespace)
+	}
+	if len(m.Metrics) > 0 {
+		for k, _ := range m.Metrics {
+			dAtA[i] = 0x12
+			i++
+			v := m.Metrics[k]
+			msgSize := 0
+			if v != nil {
+				msgSize = v.Size()
+				msgSize += 1 + sovConfig(uint64(msgSize))
+			}
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + msgSize
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if v != nil {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintConfig(dAtA, i, uint64(v.Size()))
+				n1, err1 := v.MarshalTo(dAtA[i:])
+				if err1 != nil {
+					return 0, err1
+				}
+				i += n1
+			}
+		}
+	}
+	if len(m.Logs) > 0 {
+		for k, _ := range m.Logs {
+			dAtA[i] = 0x1a
+			i++
+			v := m.Logs[k]
+			msgSize := 0
+			if v != nil {
+				msgSize = v.Size()
+				msgSize += 1 + sovConfig(uint64(msgSize))
+			}
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + msgSize
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if v != nil {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintConfig(dAtA, i, uint64(v.Size()))
+				n2, err2 := v.MarshalTo(dAtA[i:])
+				if err2 != nil {
+					return 0, err2
+				}
+				i += n2
+			}
+		}
+	}
+	if m.MetricCardinalityLimit != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MetricCardinalityLimit))
+	}
+	if m.ApiKeySecret != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ApiKeySecret.Size()))
+		n3, err3 := m.ApiKeySecret.MarshalTo(dAtA[i:])
+		if err3 != nil {
+			return 0, err3
+		}
+		i += n3
+	}
+	if m.Region != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Region))
+	}
+	if len(m.MetricsHost) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MetricsHost)))
+		i += copy(dAtA[i:], m.MetricsHost)
+	}
+	if len(m.SpansHost) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.SpansHost)))
+		i += copy(dAtA[i:], m.SpansHost)
+	}
+	if len(m.LogsHost) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LogsHost)))
+		i += copy(dAtA[i:], m.LogsHost)
+	}
+	if len(m.EventsHost) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.EventsHost)))
+		i += copy(dAtA[i:], m.EventsHost)
+	}
+	if m.TraceSampling != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.TraceSampling.Size()))
+		n4, err4 := m.TraceSampling.MarshalTo(dAtA[i:])
+		if err4 != nil {
+			return 0, err4
+		}
+		i += n4
+	}
+	if m.SpanRateLimit != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.SpanRateLimit.Size()))
+		n5, err5 := m.SpanRateLimit.MarshalTo(dAtA[i:])
+		if err5 != nil {
+			return 0, err5
+		}
+		i += n5
+	}
+	if len(m.ServiceSpanRateLimits) > 0 {
+		for k, _ := range m.ServiceSpanRateLimits {
+			dAtA[i] = 0x6a
+			i++
+			v := m.ServiceSpanRateLimits[k]
+			msgSize := 0
+			if v != nil {
+				msgSize = v.Size()
+				msgSize += 1 + sovConfig(uint64(msgSize))
+			}
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + msgSize
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if v != nil {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintConfig(dAtA, i, uint64(v.Size()))
+				n6, err6 := v.MarshalTo(dAtA[i:])
+				if err6 != nil {
+					return 0, err6
+				}
+				i += n6
+			}
+		}
+	}
+	if m.SpanMetrics != nil {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.SpanMetrics.Size()))
+		n7, err7 := m.SpanMetrics.MarshalTo(dAtA[i:])
+		if err7 != nil {
+			return 0, err7
+		}
+		i += n7
+	}
+	if m.Traces != nil {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Traces.Size()))
+		n8, err8 := m.Traces.MarshalTo(dAtA[i:])
+		if err8 != nil {
+			return 0, err8
+		}
+		i += n8
+	}
+	if m.ServiceName != nil {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ServiceName.Size()))
+		n9, err9 := m.ServiceName.MarshalTo(dAtA[i:])
+		if err9 != nil {
+			return 0, err9
+		}
+		i += n9
+	}
+	if m.ErrorSpans != nil {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ErrorSpans.Size()))
+		n10, err10 := m.ErrorSpans.MarshalTo(dAtA[i:])
+		if err10 != nil {
+			return 0, err10
+		}
+		i += n10
+	}
+	if m.IdNormalization != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.IdNormalization.Size()))
+		n11, err11 := m.IdNormalization.MarshalTo(dAtA[i:])
+		if err11 != nil {
+			return 0, err11
+		}
+		i += n11
+	}
+	if len(m.SpanPhases) > 0 {
+		for _, msg := range m.SpanPhases {
+			dAtA[i] = 0x9a
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.AccountId) > 0 {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.AccountId)))
+		i += copy(dAtA[i:], m.AccountId)
+	}
+	return i, nil
+}
+
+func (m *Params_MetricInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_MetricInfo) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if m.Type != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Type))
+	}
+	if len(m.IncludeAttributes) > 0 {
+		for _, s := range m.IncludeAttributes {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.ExcludeAttributes) > 0 {
+		for _, s := range m.ExcludeAttributes {
+			dAtA[i] = 0x22
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Buckets) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Buckets)*8))
+		for _, num := range m.Buckets {
+			f12 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f12))
+			i += 8
+		}
+	}
+	if m.ExponentialBuckets != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ExponentialBuckets.Size()))
+		n13, err13 := m.ExponentialBuckets.MarshalTo(dAtA[i:])
+		if err13 != nil {
+			return 0, err13
+		}
+		i += n13
+	}
+	if len(m.Percentiles) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Percentiles)*8))
+		for _, num := range m.Percentiles {
+			f14 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f14))
+			i += 8
+		}
+	}
+	if m.SketchMaxBins != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.SketchMaxBins))
+	}
+	if len(m.Unit) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Unit)))
+		i += copy(dAtA[i:], m.Unit)
+	}
+	return i, nil
+}
+
+func (m *Params_MetricInfo_ExponentialBuckets) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_MetricInfo_ExponentialBuckets) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Count))
+	}
+	if m.Start != 0 {
+		dAtA[i] = 0x11
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Start))))
+		i += 8
+	}
+	if m.Factor != 0 {
+		dAtA[i] = 0x19
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Factor))))
+		i += 8
+	}
+	return i, nil
+}
+
+func (m *Params_LogInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_LogInfo) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.MessageTemplate) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MessageTemplate)))
+		i += copy(dAtA[i:], m.MessageTemplate)
+	}
+	if len(m.SeverityLevels) > 0 {
+		for k, _ := range m.SeverityLevels {
+			dAtA[i] = 0x12
+			i++
+			v := m.SeverityLevels[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	return i, nil
+}
+
+func (m *Params_ApiKeySecret) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_ApiKeySecret) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Env) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Env)))
+		i += copy(dAtA[i:], m.Env)
+	}
+	if len(m.File) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.File)))
+		i += copy(dAtA[i:], m.File)
+	}
+	return i, nil
+}
+
+func (m *Params_TraceSampling) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_TraceSampling) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.HeadPercentage != 0 {
+		dAtA[i] = 0x9
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.HeadPercentage))))
+		i += 8
+	}
+	if m.Tail != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Tail.Size()))
+		n15, err15 := m.Tail.MarshalTo(dAtA[i:])
+		if err15 != nil {
+			return 0, err15
+		}
+		i += n15
+	}
+	return i, nil
+}
+
+func (m *Params_TraceSampling_TailSampling) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_TraceSampling_TailSampling) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintConfig(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.Window)))
+	n16, err16 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Window, dAtA[i:])
+	if err16 != nil {
+		return 0, err16
+	}
+	i += n16
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintConfig(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyThreshold)))
+	n17, err17 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.LatencyThreshold, dAtA[i:])
+	if err17 != nil {
+		return 0, err17
+	}
+	i += n17
+	if m.MaxTraces != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxTraces))
+	}
+	return i, nil
+}
+
+func (m *Params_SpanRateLimit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_SpanRateLimit) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.SpansPerSecond != 0 {
+		dAtA[i] = 0x9
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SpansPerSecond))))
+		i += 8
+	}
+	if m.Burst != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Burst))
+	}
+	return i, nil
+}
+
+func (m *Params_SpanMetrics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_SpanMetrics) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Namespace) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Namespace)))
+		i += copy(dAtA[i:], m.Namespace)
+	}
+	return i, nil
+}
+
+func (m *Params_Traces) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_Traces) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.NewRelicConventions {
+		dAtA[i] = 0x8
+		i++
+		if m.NewRelicConventions {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.RenameAttributes) > 0 {
+		for k, _ := range m.RenameAttributes {
+			dAtA[i] = 0x12
+			i++
+			v := m.RenameAttributes[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if len(m.IncludeAttributes) > 0 {
+		for _, s := range m.IncludeAttributes {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.ExcludeAttributes) > 0 {
+		for _, s := range m.ExcludeAttributes {
+			dAtA[i] = 0x22
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Params_ServiceName) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_ServiceName) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Server) > 0 {
+		for _, s := range m.Server {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Client) > 0 {
+		for _, s := range m.Client {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Params_ErrorSpans) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_ErrorSpans) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.ClientErrors {
+		dAtA[i] = 0x8
+		i++
+		if m.ClientErrors {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.GrpcStatusTag) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.GrpcStatusTag)))
+		i += copy(dAtA[i:], m.GrpcStatusTag)
+	}
+	if len(m.ResponseFlags) > 0 {
+		for _, s := range m.ResponseFlags {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.ResponseFlagsTag) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ResponseFlagsTag)))
+		i += copy(dAtA[i:], m.ResponseFlagsTag)
+	}
+	if len(m.MessageTag) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MessageTag)))
+		i += copy(dAtA[i:], m.MessageTag)
+	}
+	return i, nil
+}
+
+func (m *Params_IdNormalization) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_IdNormalization) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Format != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Format))
+	}
+	if m.TraceIdLength != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.TraceIdLength))
+	}
+	return i, nil
+}
+
+func (m *Params_SpanPhase) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params_SpanPhase) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.StartTag) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.StartTag)))
+		i += copy(dAtA[i:], m.StartTag)
+	}
+	if len(m.EndTag) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.EndTag)))
+		i += copy(dAtA[i:], m.EndTag)
+	}
+	return i, nil
+}
+
+func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Params) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.Metrics) > 0 {
+		for k, v := range m.Metrics {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovConfig(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Logs) > 0 {
+		for k, v := range m.Logs {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovConfig(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if m.MetricCardinalityLimit != 0 {
+		n += 1 + sovConfig(uint64(m.MetricCardinalityLimit))
+	}
+	if m.ApiKeySecret != nil {
+		l = m.ApiKeySecret.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.Region != 0 {
+		n += 1 + sovConfig(uint64(m.Region))
+	}
+	l = len(m.MetricsHost)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.SpansHost)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.LogsHost)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.EventsHost)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.TraceSampling != nil {
+		l = m.TraceSampling.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.SpanRateLimit != nil {
+		l = m.SpanRateLimit.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.ServiceSpanRateLimits) > 0 {
+		for k, v := range m.ServiceSpanRateLimits {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovConfig(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if m.SpanMetrics != nil {
+		l = m.SpanMetrics.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.Traces != nil {
+		l = m.Traces.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.ServiceName != nil {
+		l = m.ServiceName.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.ErrorSpans != nil {
+		l = m.ErrorSpans.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.IdNormalization != nil {
+		l = m.IdNormalization.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if len(m.SpanPhases) > 0 {
+		for _, e := range m.SpanPhases {
+			l = e.Size()
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	l = len(m.AccountId)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Params_MetricInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.Type != 0 {
+		n += 1 + sovConfig(uint64(m.Type))
+	}
+	if len(m.IncludeAttributes) > 0 {
+		for _, s := range m.IncludeAttributes {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.ExcludeAttributes) > 0 {
+		for _, s := range m.ExcludeAttributes {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.Buckets) > 0 {
+		n += 1 + sovConfig(uint64(len(m.Buckets)*8)) + len(m.Buckets)*8
+	}
+	if m.ExponentialBuckets != nil {
+		l = m.ExponentialBuckets.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.Percentiles) > 0 {
+		n += 1 + sovConfig(uint64(len(m.Percentiles)*8)) + len(m.Percentiles)*8
+	}
+	if m.SketchMaxBins != 0 {
+		n += 1 + sovConfig(uint64(m.SketchMaxBins))
+	}
+	l = len(m.Unit)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Params_MetricInfo_ExponentialBuckets) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Count != 0 {
+		n += 1 + sovConfig(uint64(m.Count))
+	}
+	if m.Start != 0 {
+		n += 9
+	}
+	if m.Factor != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *Params_LogInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MessageTemplate)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.SeverityLevels) > 0 {
+		for k, v := range m.SeverityLevels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *Params_ApiKeySecret) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Env)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.File)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Params_TraceSampling) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.HeadPercentage != 0 {
+		n += 9
+	}
+	if m.Tail != nil {
+		l = m.Tail.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Params_TraceSampling_TailSampling) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Window)
+	n += 1 + l + sovConfig(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.LatencyThreshold)
+	n += 1 + l + sovConfig(uint64(l))
+	if m.MaxTraces != 0 {
+		n += 1 + sovConfig(uint64(m.MaxTraces))
+	}
+	return n
+}
+
+func (m *Params_SpanRateLimit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SpansPerSecond != 0 {
+		n += 9
+	}
+	if m.Burst != 0 {
+		n += 1 + sovConfig(uint64(m.Burst))
+	}
+	return n
+}
+
+func (m *Params_SpanMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Params_Traces) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.NewRelicConventions {
+		n += 2
+	}
+	if len(m.RenameAttributes) > 0 {
+		for k, v := range m.RenameAttributes {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if len(m.IncludeAttributes) > 0 {
+		for _, s := range m.IncludeAttributes {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.ExcludeAttributes) > 0 {
+		for _, s := range m.ExcludeAttributes {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Params_ServiceName) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Server) > 0 {
+		for _, s := range m.Server {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.Client) > 0 {
+		for _, s := range m.Client {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Params_ErrorSpans) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ClientErrors {
+		n += 2
+	}
+	l = len(m.GrpcStatusTag)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.ResponseFlags) > 0 {
+		for _, s := range m.ResponseFlags {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	l = len(m.ResponseFlagsTag)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.MessageTag)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Params_IdNormalization) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Format != 0 {
+		n += 1 + sovConfig(uint64(m.Format))
+	}
+	if m.TraceIdLength != 0 {
+		n += 1 + sovConfig(uint64(m.TraceIdLength))
+	}
+	return n
+}
+
+func (m *Params_SpanPhase) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.StartTag)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.EndTag)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func sovConfig(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozConfig(x uint64) (n int) {
+	return sovConfig(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *Params) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForMetrics := make([]string, 0, len(this.Metrics))
+	for k, _ := range this.Metrics {
+		keysForMetrics = append(keysForMetrics, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForMetrics)
+	mapStringForMetrics := "map[string]*Params_MetricInfo{"
+	for _, k := range keysForMetrics {
+		mapStringForMetrics += fmt.Sprintf("%v: %v,", k, this.Metrics[k])
+	}
+	mapStringForMetrics += "}"
+	keysForLogs := make([]string, 0, len(this.Logs))
+	for k, _ := range this.Logs {
+		keysForLogs = append(keysForLogs, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForLogs)
+	mapStringForLogs := "map[string]*Params_LogInfo{"
+	for _, k := range keysForLogs {
+		mapStringForLogs += fmt.Sprintf("%v: %v,", k, this.Logs[k])
+	}
+	mapStringForLogs += "}"
+	keysForServiceSpanRateLimits := make([]string, 0, len(this.ServiceSpanRateLimits))
+	for k, _ := range this.ServiceSpanRateLimits {
+		keysForServiceSpanRateLimits = append(keysForServiceSpanRateLimits, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForServiceSpanRateLimits)
+	mapStringForServiceSpanRateLimits := "map[string]*Params_SpanRateLimit{"
+	for _, k := range keysForServiceSpanRateLimits {
+		mapStringForServiceSpanRateLimits += fmt.Sprintf("%v: %v,", k, this.ServiceSpanRateLimits[k])
+	}
+	mapStringForServiceSpanRateLimits += "}"
+	s := strings.Join([]string{`&Params{`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`Metrics:` + mapStringForMetrics + `,`,
+		`Logs:` + mapStringForLogs + `,`,
+		`MetricCardinalityLimit:` + fmt.Sprintf("%v", this.MetricCardinalityLimit) + `,`,
+		`ApiKeySecret:` + strings.Replace(fmt.Sprintf("%v", this.ApiKeySecret), "Params_ApiKeySecret", "Params_ApiKeySecret", 1) + `,`,
+		`Region:` + fmt.Sprintf("%v", this.Region) + `,`,
+		`MetricsHost:` + fmt.Sprintf("%v", this.MetricsHost) + `,`,
+		`SpansHost:` + fmt.Sprintf("%v", this.SpansHost) + `,`,
+		`LogsHost:` + fmt.Sprintf("%v", this.LogsHost) + `,`,
+		`EventsHost:` + fmt.Sprintf("%v", this.EventsHost) + `,`,
+		`TraceSampling:` + strings.Replace(fmt.Sprintf("%v", this.TraceSampling), "Params_TraceSampling", "Params_TraceSampling", 1) + `,`,
+		`SpanRateLimit:` + strings.Replace(fmt.Sprintf("%v", this.SpanRateLimit), "Params_SpanRateLimit", "Params_SpanRateLimit", 1) + `,`,
+		`ServiceSpanRateLimits:` + mapStringForServiceSpanRateLimits + `,`,
+		`SpanMetrics:` + strings.Replace(fmt.Sprintf("%v", this.SpanMetrics), "Params_SpanMetrics", "Params_SpanMetrics", 1) + `,`,
+		`Traces:` + strings.Replace(fmt.Sprintf("%v", this.Traces), "Params_Traces", "Params_Traces", 1) + `,`,
+		`ServiceName:` + strings.Replace(fmt.Sprintf("%v", this.ServiceName), "Params_ServiceName", "Params_ServiceName", 1) + `,`,
+		`ErrorSpans:` + strings.Replace(fmt.Sprintf("%v", this.ErrorSpans), "Params_ErrorSpans", "Params_ErrorSpans", 1) + `,`,
+		`IdNormalization:` + strings.Replace(fmt.Sprintf("%v", this.IdNormalization), "Params_IdNormalization", "Params_IdNormalization", 1) + `,`,
+		`SpanPhases:` + strings.Replace(fmt.Sprintf("%v", this.SpanPhases), "Params_SpanPhase", "Params_SpanPhase", 1) + `,`,
+		`AccountId:` + fmt.Sprintf("%v", this.AccountId) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_MetricInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_MetricInfo{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`IncludeAttributes:` + fmt.Sprintf("%v", this.IncludeAttributes) + `,`,
+		`ExcludeAttributes:` + fmt.Sprintf("%v", this.ExcludeAttributes) + `,`,
+		`Buckets:` + fmt.Sprintf("%v", this.Buckets) + `,`,
+		`ExponentialBuckets:` + strings.Replace(fmt.Sprintf("%v", this.ExponentialBuckets), "Params_MetricInfo_ExponentialBuckets", "Params_MetricInfo_ExponentialBuckets", 1) + `,`,
+		`Percentiles:` + fmt.Sprintf("%v", this.Percentiles) + `,`,
+		`SketchMaxBins:` + fmt.Sprintf("%v", this.SketchMaxBins) + `,`,
+		`Unit:` + fmt.Sprintf("%v", this.Unit) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_MetricInfo_ExponentialBuckets) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_MetricInfo_ExponentialBuckets{`,
+		`Count:` + fmt.Sprintf("%v", this.Count) + `,`,
+		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
+		`Factor:` + fmt.Sprintf("%v", this.Factor) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_LogInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForSeverityLevels := make([]string, 0, len(this.SeverityLevels))
+	for k, _ := range this.SeverityLevels {
+		keysForSeverityLevels = append(keysForSeverityLevels, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForSeverityLevels)
+	mapStringForSeverityLevels := "map[string]string{"
+	for _, k := range keysForSeverityLevels {
+		mapStringForSeverityLevels += fmt.Sprintf("%v: %v,", k, this.SeverityLevels[k])
+	}
+	mapStringForSeverityLevels += "}"
+	s := strings.Join([]string{`&Params_LogInfo{`,
+		`MessageTemplate:` + fmt.Sprintf("%v", this.MessageTemplate) + `,`,
+		`SeverityLevels:` + mapStringForSeverityLevels + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_ApiKeySecret) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_ApiKeySecret{`,
+		`Env:` + fmt.Sprintf("%v", this.Env) + `,`,
+		`File:` + fmt.Sprintf("%v", this.File) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_TraceSampling) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_TraceSampling{`,
+		`HeadPercentage:` + fmt.Sprintf("%v", this.HeadPercentage) + `,`,
+		`Tail:` + strings.Replace(fmt.Sprintf("%v", this.Tail), "Params_TraceSampling_TailSampling", "Params_TraceSampling_TailSampling", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_TraceSampling_TailSampling) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_TraceSampling_TailSampling{`,
+		`Window:` + strings.Replace(strings.Replace(this.Window.String(), "Duration", "types.Duration", 1), `&`, ``, 1) + `,`,
+		`LatencyThreshold:` + strings.Replace(strings.Replace(this.LatencyThreshold.String(), "Duration", "types.Duration", 1), `&`, ``, 1) + `,`,
+		`MaxTraces:` + fmt.Sprintf("%v", this.MaxTraces) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_SpanRateLimit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_SpanRateLimit{`,
+		`SpansPerSecond:` + fmt.Sprintf("%v", this.SpansPerSecond) + `,`,
+		`Burst:` + fmt.Sprintf("%v", this.Burst) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_SpanMetrics) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_SpanMetrics{`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_Traces) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForRenameAttributes := make([]string, 0, len(this.RenameAttributes))
+	for k, _ := range this.RenameAttributes {
+		keysForRenameAttributes = append(keysForRenameAttributes, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForRenameAttributes)
+	mapStringForRenameAttributes := "map[string]string{"
+	for _, k := range keysForRenameAttributes {
+		mapStringForRenameAttributes += fmt.Sprintf("%v: %v,", k, this.RenameAttributes[k])
+	}
+	mapStringForRenameAttributes += "}"
+	s := strings.Join([]string{`&Params_Traces{`,
+		`NewRelicConventions:` + fmt.Sprintf("%v", this.NewRelicConventions) + `,`,
+		`RenameAttributes:` + mapStringForRenameAttributes + `,`,
+		`IncludeAttributes:` + fmt.Sprintf("%v", this.IncludeAttributes) + `,`,
+		`ExcludeAttributes:` + fmt.Sprintf("%v", this.ExcludeAttributes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_ServiceName) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_ServiceName{`,
+		`Server:` + fmt.Sprintf("%v", this.Server) + `,`,
+		`Client:` + fmt.Sprintf("%v", this.Client) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_ErrorSpans) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_ErrorSpans{`,
+		`ClientErrors:` + fmt.Sprintf("%v", this.ClientErrors) + `,`,
+		`GrpcStatusTag:` + fmt.Sprintf("%v", this.GrpcStatusTag) + `,`,
+		`ResponseFlags:` + fmt.Sprintf("%v", this.ResponseFlags) + `,`,
+		`ResponseFlagsTag:` + fmt.Sprintf("%v", this.ResponseFlagsTag) + `,`,
+		`MessageTag:` + fmt.Sprintf("%v", this.MessageTag) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_IdNormalization) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_IdNormalization{`,
+		`Format:` + fmt.Sprintf("%v", this.Format) + `,`,
+		`TraceIdLength:` + fmt.Sprintf("%v", this.TraceIdLength) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params_SpanPhase) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params_SpanPhase{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`StartTag:` + fmt.Sprintf("%v", this.StartTag) + `,`,
+		`EndTag:` + fmt.Sprintf("%v", this.EndTag) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringConfig(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *Params) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Params: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Params: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metrics == nil {
+				m.Metrics = make(map[string]*Params_MetricInfo)
+			}
+			var mapkey string
+			var mapvalue *Params_MetricInfo
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &Params_MetricInfo{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Metrics[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Logs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Logs == nil {
+				m.Logs = make(map[string]*Params_LogInfo)
+			}
+			var mapkey string
+			var mapvalue *Params_LogInfo
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &Params_LogInfo{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Logs[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricCardinalityLimit", wireType)
+			}
+			m.MetricCardinalityLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MetricCardinalityLimit |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApiKeySecret", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ApiKeySecret == nil {
+				m.ApiKeySecret = &Params_ApiKeySecret{}
+			}
+			if err := m.ApiKeySecret.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Region", wireType)
+			}
+			m.Region = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Region |= Params_Region(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricsHost", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricsHost = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpansHost", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpansHost = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogsHost", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogsHost = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventsHost", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EventsHost = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceSampling", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TraceSampling == nil {
+				m.TraceSampling = &Params_TraceSampling{}
+			}
+			if err := m.TraceSampling.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanRateLimit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SpanRateLimit == nil {
+				m.SpanRateLimit = &Params_SpanRateLimit{}
+			}
+			if err := m.SpanRateLimit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceSpanRateLimits", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ServiceSpanRateLimits == nil {
+				m.ServiceSpanRateLimits = make(map[string]*Params_SpanRateLimit)
+			}
+			var mapkey string
+			var mapvalue *Params_SpanRateLimit
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &Params_SpanRateLimit{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ServiceSpanRateLimits[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanMetrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SpanMetrics == nil {
+				m.SpanMetrics = &Params_SpanMetrics{}
+			}
+			if err := m.SpanMetrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Traces == nil {
+				m.Traces = &Params_Traces{}
+			}
+			if err := m.Traces.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ServiceName == nil {
+				m.ServiceName = &Params_ServiceName{}
+			}
+			if err := m.ServiceName.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpans", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ErrorSpans == nil {
+				m.ErrorSpans = &Params_ErrorSpans{}
+			}
+			if err := m.ErrorSpans.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IdNormalization", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.IdNormalization == nil {
+				m.IdNormalization = &Params_IdNormalization{}
+			}
+			if err := m.IdNormalization.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanPhases", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanPhases = append(m.SpanPhases, &Params_SpanPhase{})
+			if err := m.SpanPhases[len(m.SpanPhases)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AccountId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AccountId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MetricInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MetricInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= Params_MetricInfo_Type(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeAttributes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IncludeAttributes = append(m.IncludeAttributes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExcludeAttributes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExcludeAttributes = append(m.ExcludeAttributes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.Buckets = append(m.Buckets, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthConfig
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthConfig
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 8
+				if elementCount != 0 && len(m.Buckets) == 0 {
+					m.Buckets = make([]float64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.Buckets = append(m.Buckets, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Buckets", wireType)
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExponentialBuckets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ExponentialBuckets == nil {
+				m.ExponentialBuckets = &Params_MetricInfo_ExponentialBuckets{}
+			}
+			if err := m.ExponentialBuckets.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.Percentiles = append(m.Percentiles, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthConfig
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthConfig
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 8
+				if elementCount != 0 && len(m.Percentiles) == 0 {
+					m.Percentiles = make([]float64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.Percentiles = append(m.Percentiles, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Percentiles", wireType)
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SketchMaxBins", wireType)
+			}
+			m.SketchMaxBins = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SketchMaxBins |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_MetricInfo_ExponentialBuckets) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExponentialBuckets: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExponentialBuckets: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Start = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Factor", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Factor = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_LogInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LogInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LogInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageTemplate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MessageTemplate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SeverityLevels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SeverityLevels == nil {
+				m.SeverityLevels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.SeverityLevels[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_ApiKeySecret) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ApiKeySecret: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ApiKeySecret: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Env", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Env = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.File = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_TraceSampling) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceSampling: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceSampling: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeadPercentage", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.HeadPercentage = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tail", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tail == nil {
+				m.Tail = &Params_TraceSampling_TailSampling{}
+			}
+			if err := m.Tail.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_TraceSampling_TailSampling) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TailSampling: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TailSampling: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Window, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyThreshold", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.LatencyThreshold, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxTraces", wireType)
+			}
+			m.MaxTraces = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxTraces |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_SpanRateLimit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanRateLimit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanRateLimit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpansPerSecond", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SpansPerSecond = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
+			}
+			m.Burst = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Burst |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_SpanMetrics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanMetrics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_Traces) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Traces: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Traces: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewRelicConventions", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NewRelicConventions = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RenameAttributes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RenameAttributes == nil {
+				m.RenameAttributes = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.RenameAttributes[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeAttributes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IncludeAttributes = append(m.IncludeAttributes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExcludeAttributes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExcludeAttributes = append(m.ExcludeAttributes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_ServiceName) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ServiceName: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ServiceName: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Server", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Server = append(m.Server, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Client", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Client = append(m.Client, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params_ErrorSpans) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ErrorSpans: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ErrorSpans: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientErrors", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ClientErrors = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GrpcStatusTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GrpcStatusTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseFlags", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResponseFlags = append(m.ResponseFlags, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseFlagsTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResponseFlagsTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MessageTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
-func sozConfig(x uint64) (n int) {
-	return sovConfig(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *Params) String() string {
-	if this == nil {
-		return "nil"
-	}
-	keysForMetrics := make([]string, 0, len(this.Metrics))
-	for k, _ := range this.Metrics {
-		keysForMetrics = append(keysForMetrics, k)
-	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForMetrics)
-	mapStringForMetrics := "map[string]*Params_MetricInfo{"
-	for _, k := range keysForMetrics {
-		mapStringForMetrics += fmt.Sprintf("%v: %v,", k, this.Metrics[k])
-	}
-	mapStringForMetrics += "}"
-	s := strings.Join([]string{`&Params{`,
-		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
-		`Metrics:` + mapStringForMetrics + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Params_MetricInfo) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&Params_MetricInfo{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringConfig(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *Params) Unmarshal(dAtA []byte) error {
+func (m *Params_IdNormalization) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -566,17 +6703,17 @@ func (m *Params) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Params: wiretype end group for non-group")
+			return fmt.Errorf("proto: IdNormalization: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Params: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: IdNormalization: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Format", wireType)
 			}
-			var stringLen uint64
+			m.Format = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -586,29 +6723,16 @@ func (m *Params) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Format |= Params_IdNormalization_Format(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthConfig
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthConfig
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Namespace = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceIdLength", wireType)
 			}
-			var msglen int
+			m.TraceIdLength = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -618,121 +6742,11 @@ func (m *Params) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.TraceIdLength |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthConfig
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthConfig
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Metrics == nil {
-				m.Metrics = make(map[string]*Params_MetricInfo)
-			}
-			var mapkey string
-			var mapvalue *Params_MetricInfo
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowConfig
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowConfig
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthConfig
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthConfig
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowConfig
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthConfig
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthConfig
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &Params_MetricInfo{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipConfig(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthConfig
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
-			}
-			m.Metrics[mapkey] = mapvalue
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -757,7 +6771,7 @@ func (m *Params) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
+func (m *Params_SpanPhase) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -780,10 +6794,10 @@ func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: MetricInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanPhase: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MetricInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanPhase: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -819,10 +6833,10 @@ func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
 			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTag", wireType)
 			}
-			m.Type = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -832,11 +6846,56 @@ func (m *Params_MetricInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= Params_MetricInfo_Type(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StartTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EndTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
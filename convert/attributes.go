@@ -0,0 +1,64 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"path"
+)
+
+// AttributeFilter limits attributes to those matching the Include glob
+// patterns and not matching the Exclude glob patterns.
+type AttributeFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// ValidatePattern returns an error if pattern is not a valid glob.
+func ValidatePattern(pattern string) error {
+	_, err := path.Match(pattern, "")
+	return err
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		// Patterns are validated with ValidatePattern when handlers are
+		// built.
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Keep reports whether the named attribute passes the filter.
+func (f AttributeFilter) Keep(name string) bool {
+	if len(f.Include) > 0 && !matchAny(f.Include, name) {
+		return false
+	}
+	return !matchAny(f.Exclude, name)
+}
+
+// Apply removes all attributes that do not pass the filter from attrs.
+func (f AttributeFilter) Apply(attrs map[string]interface{}) map[string]interface{} {
+	if len(f.Include) == 0 && len(f.Exclude) == 0 {
+		return attrs
+	}
+	for name := range attrs {
+		if !f.Keep(name) {
+			delete(attrs, name)
+		}
+	}
+	return attrs
+}
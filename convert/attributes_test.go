@@ -0,0 +1,89 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAttributeFilterApply(t *testing.T) {
+	attrs := func() map[string]interface{} {
+		return map[string]interface{}{
+			"source.workload.name":      "productpage-v1",
+			"destination.workload.name": "reviews-v2",
+			"destination.ip":            "10.0.0.1",
+			"request.path":              "/reviews/0",
+			"response.code":             int64(200),
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		filter   AttributeFilter
+		expected map[string]interface{}
+	}{
+		{
+			name:     "no filter",
+			filter:   AttributeFilter{},
+			expected: attrs(),
+		},
+		{
+			name:   "include exact",
+			filter: AttributeFilter{Include: []string{"response.code"}},
+			expected: map[string]interface{}{
+				"response.code": int64(200),
+			},
+		},
+		{
+			name:   "include glob",
+			filter: AttributeFilter{Include: []string{"*.workload.name"}},
+			expected: map[string]interface{}{
+				"source.workload.name":      "productpage-v1",
+				"destination.workload.name": "reviews-v2",
+			},
+		},
+		{
+			name:   "exclude glob",
+			filter: AttributeFilter{Exclude: []string{"request.*", "destination.ip"}},
+			expected: map[string]interface{}{
+				"source.workload.name":      "productpage-v1",
+				"destination.workload.name": "reviews-v2",
+				"response.code":             int64(200),
+			},
+		},
+		{
+			name: "exclude wins over include",
+			filter: AttributeFilter{
+				Include: []string{"destination.*"},
+				Exclude: []string{"destination.ip"},
+			},
+			expected: map[string]interface{}{
+				"destination.workload.name": "reviews-v2",
+			},
+		},
+		{
+			name:     "include nothing matching",
+			filter:   AttributeFilter{Include: []string{"connection.*"}},
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.filter.Apply(attrs()); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %#v, got %#v", tc.name, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	policy "istio.io/api/policy/v1beta1"
+)
+
+type unitKind int
+
+const (
+	timeUnit unitKind = iota + 1
+	dataUnit
+)
+
+// Unit is a unit of measure metric values can be converted to.
+type Unit struct {
+	name string
+	kind unitKind
+	// size is the size of the unit in nanoseconds for time units and in
+	// bytes for data units.
+	size float64
+}
+
+// String returns the name of the unit.
+func (u Unit) String() string {
+	return u.name
+}
+
+var units = map[string]Unit{
+	"ns":    {"ns", timeUnit, 1},
+	"us":    {"us", timeUnit, 1e3},
+	"ms":    {"ms", timeUnit, 1e6},
+	"s":     {"s", timeUnit, 1e9},
+	"min":   {"min", timeUnit, 60e9},
+	"h":     {"h", timeUnit, 3600e9},
+	"bytes": {"bytes", dataUnit, 1},
+	"KB":    {"KB", dataUnit, 1e3},
+	"MB":    {"MB", dataUnit, 1e6},
+	"GB":    {"GB", dataUnit, 1e9},
+	"KiB":   {"KiB", dataUnit, 1 << 10},
+	"MiB":   {"MiB", dataUnit, 1 << 20},
+	"GiB":   {"GiB", dataUnit, 1 << 30},
+}
+
+// millisecond is the unit numeric time values are assumed to be in.
+var millisecond = units["ms"]
+
+// ParseUnit returns the Unit named name.
+func ParseUnit(name string) (Unit, error) {
+	u, ok := units[name]
+	if !ok {
+		names := make([]string, 0, len(units))
+		for n := range units {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Unit{}, fmt.Errorf("unknown unit %q, must be one of: %s", name, strings.Join(names, ", "))
+	}
+	return u, nil
+}
+
+// ValueToUnit returns a metric value as a float64 in the unit u.
+//
+// Duration values are converted from their exact length. Numeric values
+// are assumed to be milliseconds for time units, matching the unit
+// durations are otherwise reported in, and bytes for data units.
+func ValueToUnit(in *policy.Value, u Unit) (float64, error) {
+	if d, ok := in.GetValue().(*policy.Value_DurationValue); ok {
+		if u.kind != timeUnit {
+			return 0.0, fmt.Errorf("unable to convert duration to %s", u)
+		}
+		nanos := float64(d.DurationValue.Value.Seconds)*1e9 + float64(d.DurationValue.Value.Nanos)
+		return nanos / u.size, nil
+	}
+
+	v, err := ValueToFloat64(in)
+	if err != nil {
+		return 0.0, err
+	}
+	if u.kind == timeUnit {
+		return v * millisecond.size / u.size, nil
+	}
+	return v / u.size, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	policy "istio.io/api/policy/v1beta1"
+)
+
+func TestParseUnit(t *testing.T) {
+	for _, name := range []string{"ns", "us", "ms", "s", "min", "h", "bytes", "KB", "MB", "GB", "KiB", "MiB", "GiB"} {
+		u, err := ParseUnit(name)
+		if err != nil {
+			t.Errorf("ParseUnit(%q) error: %v", name, err)
+			continue
+		}
+		if u.String() != name {
+			t.Errorf("ParseUnit(%q): expected unit named %q, got %q", name, name, u)
+		}
+	}
+
+	if _, err := ParseUnit("furlongs"); err == nil {
+		t.Error("ParseUnit(\"furlongs\"): expected error")
+	}
+}
+
+func TestValueToUnit(t *testing.T) {
+	duration := func(d time.Duration) *policy.Value {
+		return &policy.Value{Value: &policy.Value_DurationValue{DurationValue: &policy.Duration{
+			Value: types.DurationProto(d),
+		}}}
+	}
+
+	testCases := []struct {
+		isValid  bool
+		value    *policy.Value
+		unit     string
+		expected float64
+	}{
+		{true, duration(1500 * time.Millisecond), "s", 1.5},
+		{true, duration(1500 * time.Millisecond), "ms", 1500},
+		{true, duration(2 * time.Microsecond), "ns", 2000},
+		{true, duration(90 * time.Minute), "h", 1.5},
+		{false, duration(time.Second), "bytes", 0},
+		{true, &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 2500}}, "s", 2.5},
+		{true, &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 2048}}, "KiB", 2},
+		{true, &policy.Value{Value: &policy.Value_DoubleValue{DoubleValue: 1500}}, "KB", 1.5},
+		{true, &policy.Value{Value: &policy.Value_StringValue{StringValue: "3000000"}}, "MB", 3},
+		{false, &policy.Value{Value: &policy.Value_BoolValue{BoolValue: true}}, "bytes", 0},
+	}
+
+	for _, tc := range testCases {
+		u, err := ParseUnit(tc.unit)
+		if err != nil {
+			t.Fatalf("ParseUnit(%q) error: %v", tc.unit, err)
+		}
+
+		result, err := ValueToUnit(tc.value, u)
+		if tc.isValid {
+			if err != nil {
+				t.Errorf("ValueToUnit(%v, %s) error: %v", tc.value, u, err)
+			} else if result != tc.expected {
+				t.Errorf("ValueToUnit(%v, %s): expected %v got %v", tc.value, u, tc.expected, result)
+			}
+		} else if err == nil {
+			t.Errorf("ValueToUnit(%v, %s): expected error, got %v", tc.value, u, result)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edge
+
+import (
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+)
+
+// BuildHandler returns an edge Handler with valid configuration. Edges
+// are only reported if accountID, the New Relic account the Event API
+// requires, is set.
+func BuildHandler(_ *config.Params, h *ingest.Harvester, accountID string) (*Handler, error) {
+	edgeHandler := &Handler{
+		harvester: h,
+		accountID: accountID,
+	}
+	return edgeHandler, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	"github.com/newrelic/newrelic-istio-adapter/log"
+	"istio.io/istio/mixer/template/edge"
+)
+
+// EventType is the New Relic event type edges are reported as.
+const EventType = "IstioServiceEdge"
+
+var errAccountIDUnset = errors.New("an account ID is required to report edges: set --account-id or the account_id handler param")
+
+// Handler represents a processor that can handle edges from Istio and transmit them to New Relic.
+type Handler struct {
+	harvester *ingest.Harvester
+	accountID string
+}
+
+// HandleEdge transforms edge template instances into New Relic events and
+// sends them to New Relic. Duplicate edges are only sent once per harvest.
+// Edges are refused if no account ID is set.
+func (h *Handler) HandleEdge(_ context.Context, msgs []*edge.InstanceMsg) error {
+	if h.accountID == "" {
+		return errAccountIDUnset
+	}
+	for _, i := range msgs {
+		event, err := convertEdge(i)
+		if err != nil {
+			log.Warnf("error converting edge: %v", err)
+			continue
+		}
+
+		if err := h.harvester.RecordEvent(*event); err != nil {
+			log.Warnf("error recording edge: %v", err)
+		}
+	}
+	return nil
+}
+
+// convertEdge will convert an edge.InstanceMsg into an ingest.Event.
+func convertEdge(i *edge.InstanceMsg) (*ingest.Event, error) {
+	var timestamp time.Time
+	if ts := i.Timestamp.GetValue(); ts != nil {
+		var err error
+		if timestamp, err = types.TimestampFromProto(ts); err != nil {
+			return nil, err
+		}
+	}
+
+	attributes := map[string]interface{}{
+		"source.workload.namespace":      i.SourceWorkloadNamespace,
+		"source.workload.name":           i.SourceWorkloadName,
+		"source.owner":                   i.SourceOwner,
+		"source.uid":                     i.SourceUid,
+		"destination.workload.namespace": i.DestinationWorkloadNamespace,
+		"destination.workload.name":      i.DestinationWorkloadName,
+		"destination.owner":              i.DestinationOwner,
+		"destination.uid":                i.DestinationUid,
+		"destination.service.namespace":  i.DestinationServiceNamespace,
+		"destination.service.name":       i.DestinationServiceName,
+		"context.protocol":               i.ContextProtocol,
+		"api.protocol":                   i.ApiProtocol,
+	}
+
+	// Every attribute is part of the identity of an edge.
+	key := strings.Join([]string{
+		i.SourceWorkloadNamespace,
+		i.SourceWorkloadName,
+		i.SourceOwner,
+		i.SourceUid,
+		i.DestinationWorkloadNamespace,
+		i.DestinationWorkloadName,
+		i.DestinationOwner,
+		i.DestinationUid,
+		i.DestinationServiceNamespace,
+		i.DestinationServiceName,
+		i.ContextProtocol,
+		i.ApiProtocol,
+	}, "\x00")
+
+	return &ingest.Event{
+		EventType:  EventType,
+		Timestamp:  timestamp,
+		Attributes: attributes,
+		Key:        key,
+	}, nil
+}
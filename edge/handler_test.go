@@ -0,0 +1,115 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edge
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/edge"
+)
+
+func TestConvertEdge(t *testing.T) {
+	timestamp := time.Date(2020, time.August, 10, 11, 12, 30, 0, time.UTC)
+	ts, err := types.TimestampProto(timestamp)
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+
+	i := &edge.InstanceMsg{
+		Name:                         "edge.instance.istio-system",
+		Timestamp:                    &policy.TimeStamp{Value: ts},
+		SourceWorkloadNamespace:      "default",
+		SourceWorkloadName:           "frontend",
+		SourceOwner:                  "kubernetes://apis/apps/v1/namespaces/default/deployments/frontend",
+		SourceUid:                    "kubernetes://frontend-5d8f6f7d8b-abcde.default",
+		DestinationWorkloadNamespace: "default",
+		DestinationWorkloadName:      "cart",
+		DestinationOwner:             "kubernetes://apis/apps/v1/namespaces/default/deployments/cart",
+		DestinationUid:               "kubernetes://cart-7c9f8d6b5c-fghij.default",
+		DestinationServiceNamespace:  "default",
+		DestinationServiceName:       "cart",
+		ContextProtocol:              "http",
+		ApiProtocol:                  "grpc",
+	}
+
+	expectedAttrs := map[string]interface{}{
+		"source.workload.namespace":      "default",
+		"source.workload.name":           "frontend",
+		"source.owner":                   "kubernetes://apis/apps/v1/namespaces/default/deployments/frontend",
+		"source.uid":                     "kubernetes://frontend-5d8f6f7d8b-abcde.default",
+		"destination.workload.namespace": "default",
+		"destination.workload.name":      "cart",
+		"destination.owner":              "kubernetes://apis/apps/v1/namespaces/default/deployments/cart",
+		"destination.uid":                "kubernetes://cart-7c9f8d6b5c-fghij.default",
+		"destination.service.namespace":  "default",
+		"destination.service.name":       "cart",
+		"context.protocol":               "http",
+		"api.protocol":                   "grpc",
+	}
+
+	actual, err := convertEdge(i)
+	if err != nil {
+		t.Fatalf("failed to convert edge: %v", err)
+	}
+
+	if actual.EventType != EventType {
+		t.Errorf("expected EventType '%s', got '%s'", EventType, actual.EventType)
+	}
+
+	if !actual.Timestamp.Equal(timestamp) {
+		t.Errorf("expected Timestamp '%s', got '%s'", timestamp, actual.Timestamp)
+	}
+
+	if !reflect.DeepEqual(actual.Attributes, expectedAttrs) {
+		t.Errorf("expected attributes '%#v', got '%#v'", expectedAttrs, actual.Attributes)
+	}
+
+	// The same edge seen later must collapse to the same key.
+	later := *i
+	later.Timestamp = &policy.TimeStamp{Value: types.TimestampNow()}
+	duplicate, err := convertEdge(&later)
+	if err != nil {
+		t.Fatalf("failed to convert edge: %v", err)
+	}
+	if duplicate.Key != actual.Key {
+		t.Errorf("expected duplicate edge key '%s', got '%s'", actual.Key, duplicate.Key)
+	}
+
+	// A different destination must not.
+	other := *i
+	other.DestinationWorkloadName = "checkout"
+	different, err := convertEdge(&other)
+	if err != nil {
+		t.Fatalf("failed to convert edge: %v", err)
+	}
+	if different.Key == actual.Key {
+		t.Errorf("expected distinct edges to have different keys, both got '%s'", actual.Key)
+	}
+}
+
+func TestHandleEdgeRequiresAccountID(t *testing.T) {
+	h, err := BuildHandler(nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.HandleEdge(context.Background(), []*edge.InstanceMsg{{}}); err != errAccountIDUnset {
+		t.Errorf("expected %v, got %v", errAccountIDUnset, err)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export provides the backends metrics and spans converted from
+// Istio instances are sent to.
+package export
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+var (
+	errSpanIDUnset  = errors.New("span id must be set")
+	errTraceIDUnset = errors.New("trace id must be set")
+)
+
+// Exporter sends metrics and spans to a telemetry backend.
+type Exporter interface {
+	// RecordMetric records m to be sent on the next flush. Metrics are
+	// not aggregated with any other recorded metrics.
+	RecordMetric(m telemetry.Metric)
+	// RecordSpan records s to be sent on the next flush.
+	RecordSpan(s telemetry.Span) error
+	// Flush sends all recorded data. It blocks until the data has been
+	// sent or ctx is done.
+	Flush(ctx context.Context)
+	// Close stops flushing periodically and flushes all recorded data. It
+	// blocks until the data has been sent or ctx is done. It is safe to
+	// call more than once.
+	Close(ctx context.Context)
+}
+
+// Harvester is an Exporter that sends data to New Relic with a Telemetry
+// SDK harvester.
+type Harvester struct {
+	*telemetry.Harvester
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewHarvester returns an Exporter that sends data with h. If
+// harvestPeriod is not zero, recorded data is sent every harvestPeriod
+// until the Harvester is closed, otherwise only when Flush is called. The
+// harvest period of h should be zero, as the Telemetry SDK does not stop
+// harvesting.
+func NewHarvester(h *telemetry.Harvester, harvestPeriod time.Duration) *Harvester {
+	e := &Harvester{Harvester: h, done: make(chan struct{})}
+	if harvestPeriod != 0 {
+		e.wg.Add(1)
+		go e.harvestRoutine(harvestPeriod)
+	}
+	return e
+}
+
+// Flush sends all recorded data to New Relic.
+func (h *Harvester) Flush(ctx context.Context) {
+	h.HarvestNow(ctx)
+}
+
+// Close stops sending recorded data every harvest period and sends all
+// recorded data to New Relic.
+func (h *Harvester) Close(ctx context.Context) {
+	h.closeOnce.Do(func() {
+		close(h.done)
+		h.wg.Wait()
+	})
+	h.HarvestNow(ctx)
+}
+
+// harvestRoutine harvests every period until the Harvester is closed.
+func (h *Harvester) harvestRoutine(period time.Duration) {
+	defer h.wg.Done()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.HarvestNow(context.Background())
+		case <-h.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/log"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+// JSON is an Exporter that writes recorded data to an io.Writer as newline
+// delimited JSON. Each line is a payload in the shape accepted by the New
+// Relic Metric or Trace API. It is intended for debugging.
+type JSON struct {
+	// These fields are not modified after JSON creation.
+	commonAttributes map[string]interface{}
+
+	// writeLock serializes writes to w.
+	writeLock sync.Mutex
+	w         io.Writer
+
+	// lock protects the mutable fields below.
+	lock      sync.Mutex
+	lastFlush time.Time
+	metrics   []telemetry.Metric
+	spans     []telemetry.Span
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewJSON returns a JSON Exporter writing to w. If harvestPeriod is not
+// zero, recorded data is written every harvestPeriod, otherwise only when
+// Flush is called. The commonAttributes are applied to all data written.
+func NewJSON(w io.Writer, harvestPeriod time.Duration, commonAttributes map[string]interface{}) *JSON {
+	j := &JSON{
+		commonAttributes: commonAttributes,
+		w:                w,
+		lastFlush:        time.Now(),
+		done:             make(chan struct{}),
+	}
+	if harvestPeriod != 0 {
+		j.wg.Add(1)
+		go j.flushRoutine(harvestPeriod)
+	}
+	return j
+}
+
+// RecordMetric records m to be written on the next flush.
+func (j *JSON) RecordMetric(m telemetry.Metric) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.metrics = append(j.metrics, m)
+}
+
+// RecordSpan records s to be written on the next flush.
+func (j *JSON) RecordSpan(s telemetry.Span) error {
+	if s.TraceID == "" {
+		return errTraceIDUnset
+	}
+	if s.ID == "" {
+		return errSpanIDUnset
+	}
+	if s.Timestamp.IsZero() {
+		s.Timestamp = time.Now()
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.spans = append(j.spans, s)
+	return nil
+}
+
+// Flush writes all recorded data. Metrics and spans are each written as a
+// single line.
+func (j *JSON) Flush(_ context.Context) {
+	now := time.Now()
+	j.lock.Lock()
+	lastFlush := j.lastFlush
+	j.lastFlush = now
+	metrics := j.metrics
+	j.metrics = nil
+	spans := j.spans
+	j.spans = nil
+	j.lock.Unlock()
+
+	if len(metrics) > 0 {
+		j.write(metricPayload(metrics, j.commonAttributes, lastFlush, now.Sub(lastFlush)))
+	}
+	if len(spans) > 0 {
+		j.write(spanPayload(spans, j.commonAttributes))
+	}
+}
+
+// Close stops writing recorded data every harvest period and writes all
+// recorded data.
+func (j *JSON) Close(ctx context.Context) {
+	j.closeOnce.Do(func() {
+		close(j.done)
+		j.wg.Wait()
+	})
+	j.Flush(ctx)
+}
+
+// flushRoutine flushes every period until the JSON is closed.
+func (j *JSON) flushRoutine(period time.Duration) {
+	defer j.wg.Done()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.Flush(context.Background())
+		case <-j.done:
+			return
+		}
+	}
+}
+
+// write writes payload to w as a single line.
+func (j *JSON) write(payload interface{}) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		log.Errorf("error encoding payload: %v", err)
+		return
+	}
+	b = append(b, '\n')
+
+	j.writeLock.Lock()
+	defer j.writeLock.Unlock()
+	if _, err := j.w.Write(b); err != nil {
+		log.Errorf("error writing payload: %v", err)
+	}
+}
+
+// metricPayload returns the Metric API payload for metrics recorded during
+// the interval starting at timestamp.
+func metricPayload(metrics []telemetry.Metric, common map[string]interface{}, timestamp time.Time, interval time.Duration) interface{} {
+	c := map[string]interface{}{
+		"timestamp":   milliseconds(timestamp),
+		"interval.ms": interval.Nanoseconds() / int64(time.Millisecond),
+	}
+	if common != nil {
+		c["attributes"] = common
+	}
+
+	ms := make([]map[string]interface{}, 0, len(metrics))
+	for _, m := range metrics {
+		v, err := metricJSON(m)
+		if err != nil {
+			log.Errorf("error encoding metric: %v", err)
+			continue
+		}
+		ms = append(ms, v)
+	}
+	return []map[string]interface{}{{"common": c, "metrics": ms}}
+}
+
+// metricJSON returns the Metric API representation of m.
+func metricJSON(m telemetry.Metric) (map[string]interface{}, error) {
+	switch t := m.(type) {
+	case *telemetry.Count:
+		return metricJSON(*t)
+	case *telemetry.Gauge:
+		return metricJSON(*t)
+	case *telemetry.Summary:
+		return metricJSON(*t)
+	}
+
+	var out map[string]interface{}
+	switch t := m.(type) {
+	case telemetry.Count:
+		out = map[string]interface{}{"name": t.Name, "type": "count", "value": t.Value}
+		addTimestampInterval(out, t.Timestamp, t.Interval)
+		addAttributes(out, t.Attributes, t.AttributesJSON)
+	case telemetry.Gauge:
+		out = map[string]interface{}{"name": t.Name, "type": "gauge", "value": t.Value}
+		addTimestampInterval(out, t.Timestamp, 0)
+		addAttributes(out, t.Attributes, t.AttributesJSON)
+	case telemetry.Summary:
+		out = map[string]interface{}{
+			"name": t.Name,
+			"type": "summary",
+			"value": map[string]interface{}{
+				"sum":   t.Sum,
+				"count": t.Count,
+				"min":   t.Min,
+				"max":   t.Max,
+			},
+		}
+		addTimestampInterval(out, t.Timestamp, t.Interval)
+		addAttributes(out, t.Attributes, t.AttributesJSON)
+	default:
+		return nil, fmt.Errorf("unknown metric type %T", m)
+	}
+	return out, nil
+}
+
+// spanPayload returns the Trace API payload for spans.
+func spanPayload(spans []telemetry.Span, common map[string]interface{}) interface{} {
+	c := map[string]interface{}{}
+	if common != nil {
+		c["attributes"] = common
+	}
+
+	ss := make([]map[string]interface{}, 0, len(spans))
+	for _, s := range spans {
+		attrs := make(map[string]interface{}, len(s.Attributes)+4)
+		for k, v := range s.Attributes {
+			attrs[k] = v
+		}
+		if s.Name != "" {
+			attrs["name"] = s.Name
+		}
+		if s.ParentID != "" {
+			attrs["parent.id"] = s.ParentID
+		}
+		if s.Duration != 0 {
+			attrs["duration.ms"] = s.Duration.Seconds() * 1000.0
+		}
+		if s.ServiceName != "" {
+			attrs["service.name"] = s.ServiceName
+		}
+		ss = append(ss, map[string]interface{}{
+			"id":         s.ID,
+			"trace.id":   s.TraceID,
+			"timestamp":  milliseconds(s.Timestamp),
+			"attributes": attrs,
+		})
+	}
+	return []map[string]interface{}{{"common": c, "spans": ss}}
+}
+
+func addTimestampInterval(out map[string]interface{}, timestamp time.Time, interval time.Duration) {
+	if !timestamp.IsZero() {
+		out["timestamp"] = milliseconds(timestamp)
+	}
+	if interval != 0 {
+		out["interval.ms"] = interval.Nanoseconds() / int64(time.Millisecond)
+	}
+}
+
+func addAttributes(out map[string]interface{}, attrs map[string]interface{}, attrsJSON json.RawMessage) {
+	if attrs != nil {
+		out["attributes"] = attrs
+	} else if attrsJSON != nil {
+		out["attributes"] = attrsJSON
+	}
+}
+
+func milliseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
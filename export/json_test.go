@@ -0,0 +1,141 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+func TestJSONFlush(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewJSON(&buf, 0, map[string]interface{}{"cluster.name": "hotdog-stand"})
+
+	timestamp := time.Unix(1582230020, 0)
+	j.RecordMetric(telemetry.Count{
+		Name:       "request.total",
+		Attributes: map[string]interface{}{"reporter": "source"},
+		Value:      3,
+		Timestamp:  timestamp,
+		Interval:   5 * time.Second,
+	})
+	j.RecordMetric(&telemetry.Summary{Name: "request.duration", Count: 2, Sum: 3, Min: 1, Max: 2})
+	if err := j.RecordSpan(telemetry.Span{
+		ID:          "span",
+		TraceID:     "trace",
+		Name:        "GET /",
+		Timestamp:   timestamp,
+		Duration:    time.Second,
+		ServiceName: "cart",
+	}); err != nil {
+		t.Fatalf("RecordSpan errored: %v", err)
+	}
+	j.Flush(context.Background())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a metric and a span payload, got %q", buf.String())
+	}
+
+	var metrics []struct {
+		Common  map[string]interface{}   `json:"common"`
+		Metrics []map[string]interface{} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &metrics); err != nil {
+		t.Fatalf("failed to decode metric payload: %v", err)
+	}
+	expectedMetrics := []map[string]interface{}{
+		{
+			"name":        "request.total",
+			"type":        "count",
+			"value":       float64(3),
+			"timestamp":   float64(1582230020000),
+			"interval.ms": float64(5000),
+			"attributes":  map[string]interface{}{"reporter": "source"},
+		},
+		{
+			"name":  "request.duration",
+			"type":  "summary",
+			"value": map[string]interface{}{"count": float64(2), "sum": float64(3), "min": float64(1), "max": float64(2)},
+		},
+	}
+	if len(metrics) != 1 || !reflect.DeepEqual(metrics[0].Metrics, expectedMetrics) {
+		t.Errorf("expected metrics %#v, got %#v", expectedMetrics, metrics)
+	}
+	if attrs := metrics[0].Common["attributes"]; !reflect.DeepEqual(attrs, map[string]interface{}{"cluster.name": "hotdog-stand"}) {
+		t.Errorf("expected common attributes, got %#v", attrs)
+	}
+
+	var spans []struct {
+		Spans []map[string]interface{} `json:"spans"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &spans); err != nil {
+		t.Fatalf("failed to decode span payload: %v", err)
+	}
+	expectedSpans := []map[string]interface{}{{
+		"id":        "span",
+		"trace.id":  "trace",
+		"timestamp": float64(1582230020000),
+		"attributes": map[string]interface{}{
+			"name":         "GET /",
+			"duration.ms":  float64(1000),
+			"service.name": "cart",
+		},
+	}}
+	if len(spans) != 1 || !reflect.DeepEqual(spans[0].Spans, expectedSpans) {
+		t.Errorf("expected spans %#v, got %#v", expectedSpans, spans)
+	}
+
+	buf.Reset()
+	j.Flush(context.Background())
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written without recorded data, got %q", buf.String())
+	}
+}
+
+func TestJSONRecordSpanInvalid(t *testing.T) {
+	j := NewJSON(&bytes.Buffer{}, 0, nil)
+	if err := j.RecordSpan(telemetry.Span{ID: "span"}); err != errTraceIDUnset {
+		t.Errorf("expected %v, got %v", errTraceIDUnset, err)
+	}
+	if err := j.RecordSpan(telemetry.Span{TraceID: "trace"}); err != errSpanIDUnset {
+		t.Errorf("expected %v, got %v", errSpanIDUnset, err)
+	}
+}
+
+func TestJSONClose(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewJSON(&buf, time.Hour, nil)
+
+	j.RecordMetric(telemetry.Gauge{Name: "queue.depth", Value: 1, Timestamp: time.Unix(1582230020, 0)})
+	j.Close(context.Background())
+	if n := strings.Count(buf.String(), "\n"); n != 1 {
+		t.Fatalf("expected recorded metrics to be written on close, got %q", buf.String())
+	}
+
+	// Closing again only flushes.
+	buf.Reset()
+	j.Close(context.Background())
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written without recorded data, got %q", buf.String())
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"sync"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+// Recorder is an Exporter that keeps all recorded data in memory. It is
+// intended for tests. The zero value is ready to use.
+type Recorder struct {
+	lock    sync.Mutex
+	metrics []telemetry.Metric
+	spans   []telemetry.Span
+	flushes int
+}
+
+// RecordMetric records m.
+func (r *Recorder) RecordMetric(m telemetry.Metric) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// RecordSpan records s.
+func (r *Recorder) RecordSpan(s telemetry.Span) error {
+	if s.TraceID == "" {
+		return errTraceIDUnset
+	}
+	if s.ID == "" {
+		return errSpanIDUnset
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.spans = append(r.spans, s)
+	return nil
+}
+
+// Flush counts the flush. Recorded data is kept.
+func (r *Recorder) Flush(_ context.Context) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.flushes++
+}
+
+// Close counts a flush. Recorded data is kept.
+func (r *Recorder) Close(ctx context.Context) {
+	r.Flush(ctx)
+}
+
+// Metrics returns the metrics recorded.
+func (r *Recorder) Metrics() []telemetry.Metric {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]telemetry.Metric(nil), r.metrics...)
+}
+
+// Spans returns the spans recorded.
+func (r *Recorder) Spans() []telemetry.Span {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]telemetry.Span(nil), r.spans...)
+}
+
+// Flushes returns the number of times the Recorder has been flushed.
+func (r *Recorder) Flushes() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.flushes
+}
@@ -6,6 +6,7 @@ require (
 	github.com/gogo/googleapis v1.2.0 // indirect
 	github.com/gogo/protobuf v1.2.1
 	github.com/newrelic/newrelic-telemetry-sdk-go v0.1.0
+	github.com/prometheus/client_golang v0.9.3-0.20190127221311-3c4408c8b829
 	golang.org/x/tools v0.0.0-20200713011307-fd294ab11aed // indirect
 	google.golang.org/grpc v1.22.1
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
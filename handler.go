@@ -17,9 +17,14 @@ package newrelic
 
 import (
 	"context"
+	"sync"
 
+	nredge "github.com/newrelic/newrelic-istio-adapter/edge"
+	nrlogentry "github.com/newrelic/newrelic-istio-adapter/logentry"
 	nrmetric "github.com/newrelic/newrelic-istio-adapter/metric"
 	"github.com/newrelic/newrelic-istio-adapter/trace"
+	"istio.io/istio/mixer/template/edge"
+	"istio.io/istio/mixer/template/logentry"
 	"istio.io/istio/mixer/template/metric"
 	"istio.io/istio/mixer/template/tracespan"
 )
@@ -28,6 +33,18 @@ import (
 type Handler struct {
 	m *nrmetric.Handler
 	t *trace.Handler
+	l *nrlogentry.Handler
+	e *nredge.Handler
+
+	// onClose, if set, is called once the Handler is closed.
+	onClose   func()
+	closeOnce sync.Once
+
+	// lock protects the fields below. A retired Handler is closed once no
+	// request holds a reference to it.
+	lock    sync.Mutex
+	refs    int
+	retired bool
 }
 
 // HandleMetric reports metric instances to New Relic.
@@ -39,3 +56,58 @@ func (h *Handler) HandleMetric(ctx context.Context, values []*metric.InstanceMsg
 func (h *Handler) HandleTraceSpan(ctx context.Context, values []*tracespan.InstanceMsg) error {
 	return h.t.HandleTraceSpan(ctx, values)
 }
+
+// HandleLogEntry reports logentry instances to New Relic.
+func (h *Handler) HandleLogEntry(ctx context.Context, values []*logentry.InstanceMsg) error {
+	return h.l.HandleLogEntry(ctx, values)
+}
+
+// HandleEdge reports edge instances to New Relic.
+func (h *Handler) HandleEdge(ctx context.Context, values []*edge.InstanceMsg) error {
+	return h.e.HandleEdge(ctx, values)
+}
+
+// Close releases the resources held by the Handler and records any
+// aggregated data not yet recorded.
+func (h *Handler) Close() {
+	h.closeOnce.Do(func() {
+		h.t.Close()
+		h.m.Close()
+		if h.onClose != nil {
+			h.onClose()
+		}
+	})
+}
+
+// acquire takes a reference to h for a request, keeping h open until the
+// reference is released.
+func (h *Handler) acquire() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.refs++
+}
+
+// release drops a reference taken with acquire, closing h if it is retired
+// and no other request holds a reference to it.
+func (h *Handler) release() {
+	h.lock.Lock()
+	h.refs--
+	closing := h.retired && h.refs == 0
+	h.lock.Unlock()
+
+	if closing {
+		h.Close()
+	}
+}
+
+// retire closes h once no request holds a reference to it.
+func (h *Handler) retire() {
+	h.lock.Lock()
+	h.retired = true
+	closing := h.refs == 0
+	h.lock.Unlock()
+
+	if closing {
+		h.Close()
+	}
+}
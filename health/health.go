@@ -0,0 +1,157 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health tracks whether the adapter is able to deliver data to New
+// Relic and serves it as HTTP liveness and readiness endpoints.
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/newrelic/newrelic-istio-adapter/log"
+)
+
+// Checker tracks the consecutive failed posts to each New Relic endpoint
+// and the saturation of internal queues. The adapter is ready unless an
+// endpoint reached the failure threshold or a queue is saturated.
+type Checker struct {
+	threshold int
+
+	lock      sync.Mutex
+	failures  map[string]int
+	saturated map[string]bool
+	ready     bool
+	onChange  []func(ready bool)
+}
+
+// NewChecker returns a ready Checker. The adapter becomes unready once
+// threshold consecutive posts to an endpoint fail. A threshold of zero or
+// less disables the failure check.
+func NewChecker(threshold int) *Checker {
+	return &Checker{
+		threshold: threshold,
+		failures:  make(map[string]int),
+		saturated: make(map[string]bool),
+		ready:     true,
+	}
+}
+
+// OnChange registers f to be called with the new readiness whenever it
+// changes.
+func (c *Checker) OnChange(f func(ready bool)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.onChange = append(c.onChange, f)
+}
+
+// RecordPost records whether a post to endpoint succeeded.
+func (c *Checker) RecordPost(endpoint string, ok bool) {
+	c.update(func() {
+		if ok {
+			delete(c.failures, endpoint)
+		} else {
+			c.failures[endpoint]++
+		}
+	})
+}
+
+// SetSaturated records whether the named internal queue is saturated.
+func (c *Checker) SetSaturated(queue string, saturated bool) {
+	c.update(func() {
+		if saturated {
+			c.saturated[queue] = true
+		} else {
+			delete(c.saturated, queue)
+		}
+	})
+}
+
+// update applies f with the lock held and notifies the OnChange functions
+// if the readiness changed as a result.
+func (c *Checker) update(f func()) {
+	c.lock.Lock()
+	f()
+	reasons := c.reasons()
+	ready := len(reasons) == 0
+	changed := ready != c.ready
+	c.ready = ready
+	onChange := c.onChange
+	c.lock.Unlock()
+
+	if !changed {
+		return
+	}
+	if ready {
+		log.Infof("adapter is ready")
+	} else {
+		log.Warnf("adapter is not ready: %s", strings.Join(reasons, ", "))
+	}
+	for _, f := range onChange {
+		f(ready)
+	}
+}
+
+// reasons returns why the adapter is not ready. The caller must hold the
+// lock.
+func (c *Checker) reasons() []string {
+	var reasons []string
+	if c.threshold > 0 {
+		for endpoint, n := range c.failures {
+			if n >= c.threshold {
+				reasons = append(reasons, fmt.Sprintf("%d consecutive failed posts to %s", n, endpoint))
+			}
+		}
+	}
+	for queue := range c.saturated {
+		reasons = append(reasons, fmt.Sprintf("%s queue is saturated", queue))
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+// Reasons returns why the adapter is not ready, or nothing if it is.
+func (c *Checker) Reasons() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.reasons()
+}
+
+// Ready returns whether the adapter is ready.
+func (c *Checker) Ready() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.ready
+}
+
+// Register adds the liveness endpoint `/healthz` and the readiness
+// endpoint `/readyz` to mux.
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if reasons := c.Reasons(); len(reasons) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			for _, r := range reasons {
+				fmt.Fprintln(w, r)
+			}
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckerFailureThreshold(t *testing.T) {
+	c := NewChecker(3)
+	var changes []bool
+	c.OnChange(func(ready bool) { changes = append(changes, ready) })
+
+	c.RecordPost("metric-api", false)
+	c.RecordPost("metric-api", false)
+	c.RecordPost("trace-api", false)
+	if !c.Ready() {
+		t.Errorf("expected ready below the threshold, got %v", c.Reasons())
+	}
+
+	c.RecordPost("metric-api", false)
+	if c.Ready() {
+		t.Error("expected not ready at the threshold")
+	}
+
+	// A success resets the consecutive failures.
+	c.RecordPost("metric-api", true)
+	if !c.Ready() {
+		t.Errorf("expected ready after a successful post, got %v", c.Reasons())
+	}
+
+	if len(changes) != 2 || changes[0] || !changes[1] {
+		t.Errorf("expected readiness changes [false true], got %v", changes)
+	}
+}
+
+func TestCheckerSaturated(t *testing.T) {
+	c := NewChecker(0)
+	for i := 0; i < 10; i++ {
+		c.RecordPost("metric-api", false)
+	}
+	if !c.Ready() {
+		t.Error("expected failure check to be disabled")
+	}
+
+	c.SetSaturated("spans", true)
+	if c.Ready() {
+		t.Error("expected not ready with a saturated queue")
+	}
+	c.SetSaturated("spans", false)
+	if !c.Ready() {
+		t.Error("expected ready once the queue is no longer saturated")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	c := NewChecker(1)
+	mux := http.NewServeMux()
+	c.Register(mux)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	if code, _ := get("/readyz"); code != http.StatusOK {
+		t.Errorf("expected ready status 200, got %d", code)
+	}
+
+	c.RecordPost("metric-api.newrelic.com/metric/v1", false)
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || !strings.Contains(body, "metric-api.newrelic.com/metric/v1") {
+		t.Errorf("expected not ready status 503 naming the endpoint, got %d %q", code, body)
+	}
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("expected live status 200 while not ready, got %d", code)
+	}
+}
+
+// roundTripFunc is an http.RoundTripper calling itself.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransport(t *testing.T) {
+	testCases := []struct {
+		status int
+		err    error
+		ready  bool
+	}{
+		{202, nil, true},
+		{403, nil, true},
+		{404, nil, true},
+		{429, nil, false},
+		{503, nil, false},
+		{0, errors.New("connection refused"), false},
+	}
+
+	for _, tc := range testCases {
+		c := NewChecker(1)
+		tr := Transport(c, roundTripFunc(func(*http.Request) (*http.Response, error) {
+			if tc.err != nil {
+				return nil, tc.err
+			}
+			return &http.Response{StatusCode: tc.status, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil
+		}))
+		req, err := http.NewRequest("POST", "https://trace-api.newrelic.com/trace/v1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr.RoundTrip(req)
+		if c.Ready() != tc.ready {
+			t.Errorf("status %d: expected ready %v, got %v", tc.status, tc.ready, c.Ready())
+		}
+	}
+}
+
+func TestTransportKeysAccounts(t *testing.T) {
+	c := NewChecker(2)
+	tr := Transport(c, roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 503, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil
+	}))
+	for _, key := range []string{"a", "b"} {
+		req, err := http.NewRequest("POST", "https://trace-api.newrelic.com/trace/v1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Add("Api-Key", key)
+		tr.RoundTrip(req)
+	}
+	if !c.Ready() {
+		t.Errorf("expected failures of different accounts counted apart, got %v", c.Reasons())
+	}
+}
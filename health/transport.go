@@ -0,0 +1,57 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+)
+
+// transport is an http.RoundTripper recording the result of each post to
+// New Relic with a Checker.
+type transport struct {
+	base    http.RoundTripper
+	checker *Checker
+}
+
+// Transport returns an http.RoundTripper sending requests with base and
+// recording whether they succeeded with c. A nil base refers to
+// http.DefaultTransport.
+func Transport(c *Checker, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, checker: c}
+}
+
+// RoundTrip implements http.RoundTripper. Only network errors, server
+// errors and throttling count as failed posts. Other client errors, like a
+// rejected API key, are specific to the account and are not a reason for
+// the adapter to stop receiving data.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	failed := err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	t.checker.RecordPost(endpoint(req), !failed)
+	return resp, err
+}
+
+// endpoint returns the endpoint req is posted to, identified by its URL
+// and the account of its API key, which is hashed so it is not exposed.
+func endpoint(req *http.Request) string {
+	h := fnv.New32a()
+	h.Write([]byte(req.Header.Get("Api-Key")))
+	return fmt.Sprintf("%s%s (key %08x)", req.URL.Host, req.URL.Path, h.Sum32())
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultLogsURL        = "https://log-api.newrelic.com/log/v1"
+	defaultEventsURL      = "https://insights-collector.newrelic.com/v1/accounts/%s/events"
+	defaultHarvestPeriod  = 5 * time.Second
+	defaultHarvestTimeout = 15 * time.Second
+	defaultMaxBuffered    = 10000
+)
+
+// Config customizes the behavior of a Harvester.
+type Config struct {
+	// APIKey is required.
+	APIKey string
+	// AccountID is the ID of the New Relic account events are sent to. It
+	// is required to send events unless EventsURLOverride is set.
+	AccountID string
+	// Client is the http.Client used for making requests.
+	Client *http.Client
+	// HarvestTimeout is the total amount of time including retries that the
+	// Harvester may use trying to harvest data.
+	HarvestTimeout time.Duration
+	// CommonAttributes are the attributes applied to all data sent with
+	// this Config.
+	CommonAttributes map[string]interface{}
+	// HarvestPeriod controls how frequently data will be sent to New Relic.
+	// If HarvestPeriod is zero then data is only sent when
+	// Harvester.HarvestNow is called.
+	HarvestPeriod time.Duration
+	// MaxBuffered is the most logs, and the most events, held between
+	// harvests. Data recorded once it is reached is dropped. It defaults
+	// to 10000.
+	MaxBuffered int
+	// ErrorLogger receives errors that occur while harvesting.
+	ErrorLogger func(map[string]interface{})
+	// DebugLogger receives structured debug log messages.
+	DebugLogger func(map[string]interface{})
+	// AuditLogger receives structured log messages that include the
+	// uncompressed data sent to New Relic.
+	AuditLogger func(map[string]interface{})
+	// LogsURLOverride overrides the logs endpoint if not empty.
+	LogsURLOverride string
+	// EventsURLOverride overrides the events endpoint of AccountID if not
+	// empty.
+	EventsURLOverride string
+}
+
+// ConfigAPIKey sets the Config's APIKey which is required.
+func ConfigAPIKey(key string) func(*Config) {
+	return func(cfg *Config) {
+		cfg.APIKey = key
+	}
+}
+
+// ConfigCommonAttributes sets the Config's CommonAttributes.
+func ConfigCommonAttributes(attributes map[string]interface{}) func(*Config) {
+	return func(cfg *Config) {
+		cfg.CommonAttributes = attributes
+	}
+}
+
+// ConfigHarvestPeriod sets the Config's HarvestPeriod field which controls the
+// rate data is reported to New Relic.
+func ConfigHarvestPeriod(period time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.HarvestPeriod = period
+	}
+}
+
+func (cfg *Config) logError(fields map[string]interface{}) {
+	if cfg.ErrorLogger != nil {
+		cfg.ErrorLogger(fields)
+	}
+}
+
+func (cfg *Config) logDebug(fields map[string]interface{}) {
+	if cfg.DebugLogger != nil {
+		cfg.DebugLogger(fields)
+	}
+}
+
+func (cfg *Config) logAudit(fields map[string]interface{}) {
+	if cfg.AuditLogger != nil {
+		cfg.AuditLogger(fields)
+	}
+}
+
+func (cfg *Config) logsURL() string {
+	if cfg.LogsURLOverride != "" {
+		return cfg.LogsURLOverride
+	}
+	return defaultLogsURL
+}
+
+// eventsURL returns the events endpoint, or an empty string if neither
+// EventsURLOverride nor AccountID is set.
+func (cfg *Config) eventsURL() string {
+	if cfg.EventsURLOverride != "" {
+		return cfg.EventsURLOverride
+	}
+	if cfg.AccountID == "" {
+		return ""
+	}
+	return fmt.Sprintf(defaultEventsURL, url.PathEscape(cfg.AccountID))
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is a single custom event sent to the New Relic Event API.
+type Event struct {
+	// EventType is the New Relic event type. It is required.
+	EventType string
+	// Timestamp is when the event occurred. If Timestamp is not set, it
+	// will be assigned to time.Now() in Harvester.RecordEvent.
+	Timestamp time.Time
+	// Attributes is a map of attributes describing the event. The map
+	// values can be any of bool, number, or string.
+	Attributes map[string]interface{}
+	// Key identifies duplicate events. If Key is not empty, only the first
+	// event recorded with that Key is sent each harvest.
+	Key string
+}
+
+type eventBatch struct {
+	common map[string]interface{}
+	events []Event
+}
+
+// body returns the Event API payload for the batch. The Event API does not
+// support common attributes so they are added to each event.
+func (b *eventBatch) body() ([]byte, error) {
+	events := make([]map[string]interface{}, 0, len(b.events))
+	for _, e := range b.events {
+		event := make(map[string]interface{}, len(b.common)+len(e.Attributes)+2)
+		for k, v := range b.common {
+			event[k] = v
+		}
+		for k, v := range e.Attributes {
+			event[k] = v
+		}
+		event["eventType"] = e.EventType
+		event["timestamp"] = e.Timestamp.UnixNano() / int64(time.Millisecond)
+		events = append(events, event)
+	}
+	return json.Marshal(events)
+}
+
+func (b *eventBatch) split() []batch {
+	if len(b.events) < 2 {
+		return nil
+	}
+	half := len(b.events) / 2
+	return []batch{
+		&eventBatch{common: b.common, events: b.events[:half]},
+		&eventBatch{common: b.common, events: b.events[half:]},
+	}
+}
@@ -0,0 +1,378 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ingest reports data to the New Relic ingest APIs not covered by
+// the Telemetry SDK.
+package ingest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxCompressedSizeBytes is the largest payload the ingest APIs accept.
+const maxCompressedSizeBytes = 1 << 20
+
+var (
+	errAPIKeyUnset    = errors.New("APIKey is required")
+	errAccountIDUnset = errors.New("AccountID is required to send events")
+	errEventTypeUnset = errors.New("event type must be set")
+	errUnableToSplit  = errors.New("unable to split large payload further")
+
+	backoffSequenceSeconds = []int{0, 1, 2, 4, 8, 16}
+)
+
+// Harvester batches data and periodically reports it to New Relic.
+type Harvester struct {
+	// These fields are not modified after Harvester creation.
+	config           Config
+	commonAttributes map[string]interface{}
+
+	// lock protects the mutable fields below.
+	lock          sync.Mutex
+	logs          []Log
+	events        []Event
+	eventKeys     map[string]struct{}
+	droppedLogs   int
+	droppedEvents int
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewHarvester creates a new Harvester.
+func NewHarvester(options ...func(*Config)) (*Harvester, error) {
+	cfg := Config{
+		Client:         &http.Client{},
+		HarvestPeriod:  defaultHarvestPeriod,
+		HarvestTimeout: defaultHarvestTimeout,
+		MaxBuffered:    defaultMaxBuffered,
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+
+	if cfg.APIKey == "" {
+		return nil, errAPIKeyUnset
+	}
+
+	h := &Harvester{
+		config:           cfg,
+		commonAttributes: cfg.CommonAttributes,
+		done:             make(chan struct{}),
+	}
+	h.config.CommonAttributes = nil
+
+	h.config.logDebug(map[string]interface{}{
+		"event":                  "ingest harvester created",
+		"harvest-period-seconds": h.config.HarvestPeriod.Seconds(),
+		"logs-url-override":      h.config.LogsURLOverride,
+		"events-url-override":    h.config.EventsURLOverride,
+		"account-id":             h.config.AccountID,
+	})
+
+	if h.config.HarvestPeriod != 0 {
+		h.wg.Add(1)
+		go h.harvestRoutine()
+	}
+
+	return h, nil
+}
+
+// RecordLog records the given log to be sent on the next harvest. The log
+// is dropped if Config.MaxBuffered logs are already recorded.
+func (h *Harvester) RecordLog(l Log) {
+	if h == nil {
+		return
+	}
+	if l.Timestamp.IsZero() {
+		l.Timestamp = time.Now()
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if len(h.logs) >= h.config.MaxBuffered {
+		h.droppedLogs++
+		return
+	}
+	h.logs = append(h.logs, l)
+}
+
+// RecordEvent records the given event to be sent on the next harvest. Events
+// with a Key already recorded since the last harvest are dropped, as are
+// events recorded once Config.MaxBuffered events are.
+func (h *Harvester) RecordEvent(e Event) error {
+	if h == nil {
+		return nil
+	}
+	if e.EventType == "" {
+		return errEventTypeUnset
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if e.Key != "" {
+		if _, seen := h.eventKeys[e.Key]; seen {
+			return nil
+		}
+	}
+	if len(h.events) >= h.config.MaxBuffered {
+		h.droppedEvents++
+		return nil
+	}
+	if e.Key != "" {
+		if h.eventKeys == nil {
+			h.eventKeys = make(map[string]struct{})
+		}
+		h.eventKeys[e.Key] = struct{}{}
+	}
+	h.events = append(h.events, e)
+	return nil
+}
+
+// HarvestNow sends all recorded data to New Relic. This method blocks until
+// all data has been sent or the Config.HarvestTimeout has elapsed.
+func (h *Harvester) HarvestNow(ct context.Context) {
+	if h == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ct, h.config.HarvestTimeout)
+	defer cancel()
+
+	h.lock.Lock()
+	logs := h.logs
+	h.logs = nil
+	events := h.events
+	h.events = nil
+	h.eventKeys = nil
+	droppedLogs, droppedEvents := h.droppedLogs, h.droppedEvents
+	h.droppedLogs, h.droppedEvents = 0, 0
+	h.lock.Unlock()
+
+	if droppedLogs > 0 || droppedEvents > 0 {
+		h.config.logError(map[string]interface{}{
+			"message":        "buffer full, dropped data",
+			"dropped-logs":   droppedLogs,
+			"dropped-events": droppedEvents,
+		})
+	}
+
+	if len(logs) > 0 {
+		b := &logBatch{common: h.commonAttributes, logs: logs}
+		h.send(ctx, b, h.config.logsURL())
+	}
+	if len(events) > 0 {
+		if url := h.config.eventsURL(); url != "" {
+			b := &eventBatch{common: h.commonAttributes, events: events}
+			h.send(ctx, b, url)
+		} else {
+			h.config.logError(map[string]interface{}{
+				"err":     errAccountIDUnset.Error(),
+				"message": "dropping events",
+			})
+		}
+	}
+}
+
+// Close stops harvesting every Config.HarvestPeriod and sends all recorded
+// data to New Relic. It blocks until a harvest in progress has completed
+// and the data has been sent or ctx is done. Data recorded after Close is
+// only sent by calling HarvestNow.
+func (h *Harvester) Close(ctx context.Context) {
+	if h == nil {
+		return
+	}
+	h.closeOnce.Do(func() {
+		close(h.done)
+		h.wg.Wait()
+	})
+	h.HarvestNow(ctx)
+}
+
+// harvestRoutine harvests every Config.HarvestPeriod until the Harvester
+// is closed. Harvests run one at a time, so a slow endpoint delays the
+// next harvest instead of piling them up.
+func (h *Harvester) harvestRoutine() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(h.config.HarvestPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.HarvestNow(context.Background())
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// batch is a collection of data that can be encoded as a request body.
+type batch interface {
+	body() ([]byte, error)
+	split() []batch
+}
+
+// send compresses and posts b to url, splitting it if it is too large.
+func (h *Harvester) send(ctx context.Context, b batch, url string) {
+	body, err := b.body()
+	if err != nil {
+		h.config.logError(map[string]interface{}{
+			"err":     err.Error(),
+			"message": "error encoding request body",
+		})
+		return
+	}
+
+	compressed, err := compress(body)
+	if err != nil {
+		h.config.logError(map[string]interface{}{
+			"err":     err.Error(),
+			"message": "error compressing request body",
+		})
+		return
+	}
+
+	if len(compressed) >= maxCompressedSizeBytes {
+		parts := b.split()
+		if parts == nil {
+			h.config.logError(map[string]interface{}{
+				"err":     errUnableToSplit.Error(),
+				"message": "dropping data",
+			})
+			return
+		}
+		for _, p := range parts {
+			h.send(ctx, p, url)
+		}
+		return
+	}
+
+	h.config.logAudit(map[string]interface{}{
+		"event": "uncompressed request body",
+		"url":   url,
+		"data":  string(body),
+	})
+
+	for attempts := 0; ; attempts++ {
+		h.config.logDebug(map[string]interface{}{
+			"event":       "data post",
+			"url":         url,
+			"body-length": len(compressed),
+		})
+
+		retry, backoff := h.post(ctx, url, compressed, attempts)
+		if !retry {
+			return
+		}
+
+		tmr := time.NewTimer(backoff)
+		select {
+		case <-tmr.C:
+		case <-ctx.Done():
+			tmr.Stop()
+			h.config.logError(map[string]interface{}{
+				"event":         "harvest cancelled or timed out",
+				"message":       "dropping data",
+				"context-error": ctx.Err().Error(),
+			})
+			return
+		}
+	}
+}
+
+// post sends a single compressed payload and reports if it should be retried.
+func (h *Harvester) post(ctx context.Context, url string, compressed []byte, attempts int) (bool, time.Duration) {
+	if attempts >= len(backoffSequenceSeconds) {
+		attempts = len(backoffSequenceSeconds) - 1
+	}
+	backoff := time.Duration(backoffSequenceSeconds[attempts]) * time.Second
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(compressed))
+	if err != nil {
+		h.config.logError(map[string]interface{}{
+			"err":     err.Error(),
+			"message": "error creating request",
+		})
+		return false, 0
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Api-Key", h.config.APIKey)
+	req.Header.Add("Content-Encoding", "gzip")
+
+	resp, err := h.config.Client.Do(req)
+	if err != nil {
+		h.config.logError(map[string]interface{}{
+			"err": fmt.Sprintf("error posting data: %v", err),
+		})
+		return true, backoff
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	h.config.logDebug(map[string]interface{}{
+		"event":  "data post response",
+		"status": resp.StatusCode,
+		"body":   string(body),
+	})
+
+	switch resp.StatusCode {
+	case 200, 202:
+		return false, 0
+	case 400, 403, 404, 405, 411, 413:
+		h.config.logError(map[string]interface{}{
+			"err": fmt.Sprintf("unexpected post response code: %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		})
+		return false, 0
+	case 429:
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			if d := time.Duration(s) * time.Second; d > backoff {
+				return true, d
+			}
+		}
+		return true, backoff
+	default:
+		h.config.logError(map[string]interface{}{
+			"err": fmt.Sprintf("unexpected post response code: %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		})
+		return true, backoff
+	}
+}
+
+func compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
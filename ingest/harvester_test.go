@@ -0,0 +1,273 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// mockTransport caches decompressed request bodies.
+type mockTransport struct {
+	urls     []string
+	requests [][]byte
+}
+
+func (m *mockTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	gz, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	contents, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	m.urls = append(m.urls, r.URL.String())
+	m.requests = append(m.requests, contents)
+
+	return &http.Response{
+		StatusCode: 202,
+		Body:       ioutil.NopCloser(&bytes.Buffer{}),
+	}, nil
+}
+
+func newTestHarvester(t *testing.T, mockt *mockTransport) *Harvester {
+	h, err := NewHarvester(
+		ConfigAPIKey("api-key"),
+		ConfigHarvestPeriod(0),
+		ConfigCommonAttributes(map[string]interface{}{"cluster.name": "test"}),
+		func(cfg *Config) {
+			cfg.AccountID = "12345"
+			cfg.Client.Transport = mockt
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestNewHarvesterRequiresAPIKey(t *testing.T) {
+	if _, err := NewHarvester(); err != errAPIKeyUnset {
+		t.Errorf("expected %v, got %v", errAPIKeyUnset, err)
+	}
+}
+
+func TestHarvestLogs(t *testing.T) {
+	mockt := &mockTransport{}
+	h := newTestHarvester(t, mockt)
+
+	h.HarvestNow(context.Background())
+	if len(mockt.requests) != 0 {
+		t.Fatalf("expected no requests without data, got %d", len(mockt.requests))
+	}
+
+	h.RecordLog(Log{
+		Message:    "GET /cart 200",
+		Timestamp:  time.Unix(1582230020, 0),
+		Attributes: map[string]interface{}{"level": "info"},
+	})
+	h.HarvestNow(context.Background())
+
+	if len(mockt.requests) != 1 {
+		t.Fatalf("expected a single request, got %d", len(mockt.requests))
+	}
+	if mockt.urls[0] != defaultLogsURL {
+		t.Errorf("expected request to %q, got %q", defaultLogsURL, mockt.urls[0])
+	}
+
+	var actual []map[string]interface{}
+	if err := json.Unmarshal(mockt.requests[0], &actual); err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{{
+		"common": map[string]interface{}{
+			"attributes": map[string]interface{}{"cluster.name": "test"},
+		},
+		"logs": []interface{}{
+			map[string]interface{}{
+				"timestamp":  float64(1582230020000),
+				"message":    "GET /cart 200",
+				"attributes": map[string]interface{}{"level": "info"},
+			},
+		},
+	}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected payload %#v, got %#v", expected, actual)
+	}
+
+	h.HarvestNow(context.Background())
+	if len(mockt.requests) != 1 {
+		t.Errorf("expected logs to be cleared after harvest, got %d requests", len(mockt.requests))
+	}
+}
+
+func TestHarvestEvents(t *testing.T) {
+	mockt := &mockTransport{}
+	h := newTestHarvester(t, mockt)
+
+	if err := h.RecordEvent(Event{}); err != errEventTypeUnset {
+		t.Errorf("expected %v, got %v", errEventTypeUnset, err)
+	}
+
+	timestamp := time.Unix(1582230020, 0)
+	for i := 0; i < 3; i++ {
+		err := h.RecordEvent(Event{
+			EventType:  "TestEvent",
+			Timestamp:  timestamp,
+			Attributes: map[string]interface{}{"source": "a"},
+			Key:        "a",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.RecordEvent(Event{
+		EventType:  "TestEvent",
+		Timestamp:  timestamp,
+		Attributes: map[string]interface{}{"source": "b"},
+	})
+	h.HarvestNow(context.Background())
+
+	if len(mockt.requests) != 1 {
+		t.Fatalf("expected a single request, got %d", len(mockt.requests))
+	}
+	if expected := "https://insights-collector.newrelic.com/v1/accounts/12345/events"; mockt.urls[0] != expected {
+		t.Errorf("expected request to %q, got %q", expected, mockt.urls[0])
+	}
+
+	var actual []map[string]interface{}
+	if err := json.Unmarshal(mockt.requests[0], &actual); err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{
+			"eventType":    "TestEvent",
+			"timestamp":    float64(1582230020000),
+			"cluster.name": "test",
+			"source":       "a",
+		},
+		{
+			"eventType":    "TestEvent",
+			"timestamp":    float64(1582230020000),
+			"cluster.name": "test",
+			"source":       "b",
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected payload %#v, got %#v", expected, actual)
+	}
+
+	// Duplicates are only collapsed within a single harvest.
+	h.RecordEvent(Event{EventType: "TestEvent", Key: "a"})
+	h.HarvestNow(context.Background())
+	if len(mockt.requests) != 2 {
+		t.Errorf("expected keyed event to be sent after harvest, got %d requests", len(mockt.requests))
+	}
+}
+
+func TestHarvestEventsRequiresAccountID(t *testing.T) {
+	mockt := &mockTransport{}
+	h := newTestHarvester(t, mockt)
+	h.config.AccountID = ""
+
+	if err := h.RecordEvent(Event{EventType: "TestEvent"}); err != nil {
+		t.Fatal(err)
+	}
+	h.HarvestNow(context.Background())
+	if len(mockt.requests) != 0 {
+		t.Errorf("expected events without an account ID dropped, got %d requests", len(mockt.requests))
+	}
+
+	h.config.EventsURLOverride = "http://localhost/events"
+	if err := h.RecordEvent(Event{EventType: "TestEvent"}); err != nil {
+		t.Fatal(err)
+	}
+	h.HarvestNow(context.Background())
+	if len(mockt.requests) != 1 || mockt.urls[0] != "http://localhost/events" {
+		t.Errorf("expected events sent to the override, got %v", mockt.urls)
+	}
+}
+
+func TestHarvestMaxBuffered(t *testing.T) {
+	mockt := &mockTransport{}
+	h := newTestHarvester(t, mockt)
+	h.config.MaxBuffered = 2
+
+	for i := 0; i < 3; i++ {
+		h.RecordLog(Log{Message: "log"})
+		h.RecordEvent(Event{EventType: "TestEvent"})
+	}
+	h.HarvestNow(context.Background())
+
+	if len(mockt.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(mockt.requests))
+	}
+	var logs []struct{ Logs []interface{} }
+	if err := json.Unmarshal(mockt.requests[0], &logs); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(logs[0].Logs); n != 2 {
+		t.Errorf("expected 2 logs, got %d", n)
+	}
+	var events []interface{}
+	if err := json.Unmarshal(mockt.requests[1], &events); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(events); n != 2 {
+		t.Errorf("expected 2 events, got %d", n)
+	}
+
+	// The buffers accept data again once harvested.
+	h.RecordLog(Log{Message: "log"})
+	h.HarvestNow(context.Background())
+	if len(mockt.requests) != 3 {
+		t.Errorf("expected log to be sent after harvest, got %d requests", len(mockt.requests))
+	}
+}
+
+func TestHarvesterClose(t *testing.T) {
+	mockt := &mockTransport{}
+	h, err := NewHarvester(
+		ConfigAPIKey("api-key"),
+		ConfigHarvestPeriod(time.Hour),
+		func(cfg *Config) {
+			cfg.Client.Transport = mockt
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.RecordLog(Log{Message: "log"})
+	h.Close(context.Background())
+	if len(mockt.requests) != 1 {
+		t.Fatalf("expected recorded logs to be sent on close, got %d requests", len(mockt.requests))
+	}
+	// Closing again only harvests.
+	h.Close(context.Background())
+	if len(mockt.requests) != 1 {
+		t.Errorf("expected no request without data, got %d requests", len(mockt.requests))
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Log is a single log entry sent to the New Relic Log API.
+type Log struct {
+	// Message is the log message.
+	Message string
+	// Timestamp is when the log entry was created. If Timestamp is not set,
+	// it will be assigned to time.Now() in Harvester.RecordLog.
+	Timestamp time.Time
+	// Attributes is a map of attributes describing the log entry. The map
+	// values can be any of bool, number, or string.
+	Attributes map[string]interface{}
+}
+
+type logBatch struct {
+	common map[string]interface{}
+	logs   []Log
+}
+
+type logJSON struct {
+	Timestamp  int64                  `json:"timestamp"`
+	Message    string                 `json:"message"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type commonJSON struct {
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type logBatchJSON struct {
+	Common commonJSON `json:"common"`
+	Logs   []logJSON  `json:"logs"`
+}
+
+// body returns the Log API payload for the batch.
+func (b *logBatch) body() ([]byte, error) {
+	logs := make([]logJSON, 0, len(b.logs))
+	for _, l := range b.logs {
+		logs = append(logs, logJSON{
+			Timestamp:  l.Timestamp.UnixNano() / int64(time.Millisecond),
+			Message:    l.Message,
+			Attributes: l.Attributes,
+		})
+	}
+	return json.Marshal([]logBatchJSON{{
+		Common: commonJSON{Attributes: b.common},
+		Logs:   logs,
+	}})
+}
+
+func (b *logBatch) split() []batch {
+	if len(b.logs) < 2 {
+		return nil
+	}
+	half := len(b.logs) / 2
+	return []batch{
+		&logBatch{common: b.common, logs: b.logs[:half]},
+		&logBatch{common: b.common, logs: b.logs[half:]},
+	}
+}
@@ -32,6 +32,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	integration "istio.io/istio/mixer/pkg/adapter/test"
 )
@@ -39,6 +41,7 @@ import (
 const (
 	adapterConfigPath  = "config/newrelic.yaml"
 	operatorConfigPath = "integration_test_cfg.yaml"
+	edgeTemplatePath   = "integration_test_edge_template.yaml"
 )
 
 func TestReport(t *testing.T) {
@@ -52,6 +55,11 @@ func TestReport(t *testing.T) {
 		t.Fatalf("failed to read operator config file: %v", err)
 	}
 
+	edgeTemplateCfg, err := ioutil.ReadFile(edgeTemplatePath)
+	if err != nil {
+		t.Fatalf("failed to read edge template config file: %v", err)
+	}
+
 	var commonAttrs = map[string]interface{}{
 		"cluster.name":      "hotdog-stand",
 		"foodHandlerPermit": "revoked",
@@ -75,9 +83,20 @@ func TestReport(t *testing.T) {
 		t.Fatalf("failed to create harvester: %v", err)
 	}
 
+	ingestHarvester, err := ingest.NewHarvester(
+		ingest.ConfigAPIKey("8675309"),
+		ingest.ConfigHarvestPeriod(0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create ingest harvester: %v", err)
+	}
+
 	scenario := integration.Scenario{
 		Setup: func() (ctx interface{}, err error) {
-			s, err := NewServer(":0", harvester)
+			build := func(Account) (export.Exporter, *ingest.Harvester, error) {
+				return export.NewHarvester(harvester, 0), ingestHarvester, nil
+			}
+			s, err := NewServer(":0", Account{APIKey: "8675309", AccountID: "12345"}, SecretConfig{}, build, 0, HandlerCacheConfig{}, QueueConfig{})
 			if err != nil {
 				return nil, err
 			}
@@ -92,7 +111,7 @@ func TestReport(t *testing.T) {
 
 		Teardown: func(ctx interface{}) {
 			s := ctx.(*Server)
-			s.Close()
+			s.Close(context.Background())
 		},
 
 		ParallelCalls: []integration.Call{
@@ -113,6 +132,9 @@ func TestReport(t *testing.T) {
 
 		GetState: func(ctx interface{}) (interface{}, error) {
 			// send metrics immediately
+			for _, e := range ctx.(*Server).handlers.entries {
+				e.handler.m.Flush()
+			}
 			harvester.HarvestNow(context.Background())
 
 			// verify a single request was issued with the correct metric data
@@ -136,9 +158,11 @@ func TestReport(t *testing.T) {
 		GetConfig: func(ctx interface{}) ([]string, error) {
 			// This supplies the adapter config and some operator config for instances and rules
 			// Metric template config is required and automatically added by the test framework
+			// The edge template is not, so it is supplied here
 			s := ctx.(*Server)
 			return []string{
 				string(adapterCfg),
+				string(edgeTemplateCfg),
 				fmt.Sprintf(string(operatorCfg), s.listener.Addr().String()),
 			}, nil
 		},
@@ -210,7 +234,8 @@ func TestReport(t *testing.T) {
              "source.principal": "unknown",
              "source.version": "unknown",
              "source.workload": "unknown",
-             "source.workload.namespace": "unknown"
+             "source.workload.namespace": "unknown",
+             "unit": "s"
             },
             "name": "istio.request.duration.seconds",
             "type": "summary",
@@ -3,6 +3,7 @@ package log
 import (
 	"encoding/json"
 
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	iLog "istio.io/pkg/log"
 )
@@ -23,10 +24,22 @@ func init() {
 	// Configure the default Istio logger with our default options.
 	// This ensures things like gRPC logging (which is overriden by the
 	// Istio log package) is configured at the appropriate level.
+	_ = iLog.Configure(defaultOptions())
+}
+
+func defaultOptions() *iLog.Options {
 	opts := iLog.DefaultOptions()
 	opts.SetOutputLevel(iLog.DefaultScopeName, defaultOutputLevel.istioLevel())
 	opts.SetStackTraceLevel(iLog.DefaultScopeName, defaultStackTraceLevel.istioLevel())
-	_ = iLog.Configure(opts)
+	return opts
+}
+
+// SetOutputPaths sets the paths log messages are written to, e.g. stderr.
+// Messages are written to stdout by default.
+func SetOutputPaths(paths ...string) error {
+	opts := defaultOptions()
+	opts.OutputPaths = paths
+	return iLog.Configure(opts)
 }
 
 // Fatalf uses fmt.Sprintf to construct and log a message at fatal level.
@@ -87,3 +100,13 @@ func HarvesterConfigFunc() func(*telemetry.Config) {
 		cfg.AuditLogger = telemetryLogger(adapterScope.Debugf)
 	}
 }
+
+// IngestConfigFunc returns a configuration function for the ingest Harvester
+// initialization. Logging levels are mapped the same as HarvesterConfigFunc.
+func IngestConfigFunc() func(*ingest.Config) {
+	return func(cfg *ingest.Config) {
+		cfg.ErrorLogger = telemetryLogger(adapterScope.Errorf)
+		cfg.DebugLogger = telemetryLogger(adapterScope.Infof)
+		cfg.AuditLogger = telemetryLogger(adapterScope.Debugf)
+	}
+}
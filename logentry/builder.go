@@ -0,0 +1,73 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logentry
+
+import (
+	"text/template"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	"github.com/newrelic/newrelic-istio-adapter/log"
+	"istio.io/istio/mixer/pkg/adapter"
+)
+
+type logConfig struct {
+	logs map[string]info
+}
+
+// BuildHandler returns a logentry Handler with valid configuration.
+func BuildHandler(params *config.Params, h *ingest.Harvester) (*Handler, error) {
+	cfg, err := buildConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("built logs: %#v", cfg.logs)
+
+	handler := &Handler{
+		harvester: h,
+		logs:      cfg.logs,
+	}
+	return handler, nil
+}
+
+// buildConfig returns a valid logConfig. It iterates through the logs from
+// config.Params and validates them. If any of the logs are invalid, it
+// returns nil instead.
+func buildConfig(params *config.Params) (cfg *logConfig, errs *adapter.ConfigErrors) {
+	cfg = &logConfig{
+		logs: make(map[string]info, len(params.GetLogs())),
+	}
+
+	for instName, lInfo := range params.GetLogs() {
+		var tmpl *template.Template
+		if t := lInfo.GetMessageTemplate(); t != "" {
+			var err error
+			tmpl, err = template.New(instName).Parse(t)
+			if err != nil {
+				errs = errs.Appendf("LogInfo.message_template", "template is invalid: %v", err)
+				cfg = nil
+				continue
+			}
+		}
+
+		if cfg != nil {
+			cfg.logs[instName] = info{
+				tmpl:   tmpl,
+				levels: lInfo.GetSeverityLevels(),
+			}
+		}
+	}
+	return cfg, errs
+}
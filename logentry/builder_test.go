@@ -0,0 +1,53 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logentry
+
+import (
+	"testing"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+)
+
+func TestBuildConfig(t *testing.T) {
+	testCases := []struct {
+		isValid bool
+		tmpl    string
+	}{
+		{true, ""},
+		{true, "{{.method}} {{.url}}"},
+		{false, "{{.method"},
+	}
+
+	for i, tc := range testCases {
+		params := &config.Params{
+			Logs: map[string]*config.Params_LogInfo{
+				"accesslog.instance.istio-system": &config.Params_LogInfo{MessageTemplate: tc.tmpl},
+			},
+		}
+
+		cfg, err := buildConfig(params)
+		if tc.isValid && err != nil {
+			t.Errorf("index %d: did not expect error, got %v", i, err)
+		}
+		if !tc.isValid && err == nil {
+			t.Errorf("index %d: expected error for template %q", i, tc.tmpl)
+		}
+		if tc.isValid && cfg != nil {
+			if _, ok := cfg.logs["accesslog.instance.istio-system"]; !ok {
+				t.Errorf("index %d: log info not found in %#v", i, cfg.logs)
+			}
+		}
+	}
+}
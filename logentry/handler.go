@@ -0,0 +1,134 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logentry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/convert"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	"istio.io/istio/mixer/template/logentry"
+)
+
+const (
+	// messageVariable is the logentry variable used as the message when no
+	// message template is configured.
+	messageVariable = "message"
+	levelAttribute  = "level"
+)
+
+// Handler represents a processor that can handle logentries from Istio and transmit them to New Relic.
+type Handler struct {
+	harvester *ingest.Harvester
+	logs      map[string]info
+}
+
+type info struct {
+	tmpl   *template.Template
+	levels map[string]string
+}
+
+type handleError struct {
+	Name string
+	Err  error
+}
+
+func (e *handleError) Error() string {
+	return fmt.Sprintf("failed to handle %q: %s", e.Name, e.Err)
+}
+
+type handleErrors []*handleError
+
+func (e handleErrors) Error() string {
+	var msg string
+	for _, err := range e {
+		msg += "\n"
+		msg += err.Error()
+	}
+	return msg
+}
+
+func (e handleErrors) ErrorOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// HandleLogEntry transforms logentry template instances into New Relic logs and
+// sends them to New Relic.
+func (h *Handler) HandleLogEntry(_ context.Context, msgs []*logentry.InstanceMsg) error {
+	var errs handleErrors
+	for _, i := range msgs {
+		linfo, found := h.logs[i.Name]
+		if !found {
+			errs = append(errs, &handleError{i.Name, errors.New("no log info found")})
+			continue
+		}
+
+		l, err := convertLogEntry(i, linfo)
+		if err != nil {
+			errs = append(errs, &handleError{i.Name, err})
+			continue
+		}
+
+		h.harvester.RecordLog(*l)
+	}
+	return errs.ErrorOrNil()
+}
+
+// convertLogEntry will convert a logentry.InstanceMsg into an ingest.Log.
+func convertLogEntry(i *logentry.InstanceMsg, linfo info) (*ingest.Log, error) {
+	var timestamp time.Time
+	if ts := i.Timestamp.GetValue(); ts != nil {
+		var err error
+		if timestamp, err = types.TimestampFromProto(ts); err != nil {
+			return nil, err
+		}
+	}
+
+	attributes := convert.DimensionsToAttributes(i.Variables)
+
+	var message string
+	if linfo.tmpl != nil {
+		var buf bytes.Buffer
+		if err := linfo.tmpl.Execute(&buf, attributes); err != nil {
+			return nil, fmt.Errorf("failed to build message: %v", err)
+		}
+		message = buf.String()
+	} else if m, ok := attributes[messageVariable]; ok {
+		message = fmt.Sprint(m)
+		delete(attributes, messageVariable)
+	}
+
+	if level := i.Severity; level != "" {
+		if mapped, ok := linfo.levels[level]; ok {
+			level = mapped
+		}
+		attributes[levelAttribute] = level
+	}
+
+	return &ingest.Log{
+		Message:    message,
+		Timestamp:  timestamp,
+		Attributes: attributes,
+	}, nil
+}
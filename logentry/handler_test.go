@@ -0,0 +1,132 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logentry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/logentry"
+)
+
+func TestConvertLogEntry(t *testing.T) {
+	timestamp := time.Date(2020, time.August, 10, 11, 12, 30, 0, time.UTC)
+	ts, err := types.TimestampProto(timestamp)
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+
+	variables := map[string]*policy.Value{
+		"method":       &policy.Value{Value: &policy.Value_StringValue{StringValue: "GET"}},
+		"url":          &policy.Value{Value: &policy.Value_StringValue{StringValue: "/cart"}},
+		"responseCode": &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 503}},
+		"message":      &policy.Value{Value: &policy.Value_StringValue{StringValue: "upstream failure"}},
+	}
+
+	testCases := []struct {
+		name        string
+		tmpl        string
+		severity    string
+		levels      map[string]string
+		wantMessage string
+		wantAttrs   map[string]interface{}
+	}{
+		{
+			name:        "template",
+			tmpl:        "{{.method}} {{.url}} {{.responseCode}}",
+			severity:    "Warning",
+			levels:      map[string]string{"Warning": "warn"},
+			wantMessage: "GET /cart 503",
+			wantAttrs: map[string]interface{}{
+				"method":       "GET",
+				"url":          "/cart",
+				"responseCode": int64(503),
+				"message":      "upstream failure",
+				"level":        "warn",
+			},
+		},
+		{
+			name:        "message variable",
+			severity:    "Error",
+			wantMessage: "upstream failure",
+			wantAttrs: map[string]interface{}{
+				"method":       "GET",
+				"url":          "/cart",
+				"responseCode": int64(503),
+				"level":        "Error",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		linfo := info{levels: tc.levels}
+		if tc.tmpl != "" {
+			linfo.tmpl = template.Must(template.New(tc.name).Parse(tc.tmpl))
+		}
+
+		i := &logentry.InstanceMsg{
+			Name:      "accesslog.instance.istio-system",
+			Variables: variables,
+			Timestamp: &policy.TimeStamp{Value: ts},
+			Severity:  tc.severity,
+		}
+
+		actual, err := convertLogEntry(i, linfo)
+		if err != nil {
+			t.Errorf("%s: failed to convert logentry: %v", tc.name, err)
+			continue
+		}
+
+		if actual.Message != tc.wantMessage {
+			t.Errorf("%s: expected message %q, got %q", tc.name, tc.wantMessage, actual.Message)
+		}
+		if !actual.Timestamp.Equal(timestamp) {
+			t.Errorf("%s: expected timestamp %s, got %s", tc.name, timestamp, actual.Timestamp)
+		}
+		if !reflect.DeepEqual(actual.Attributes, tc.wantAttrs) {
+			t.Errorf("%s: expected attributes %#v, got %#v", tc.name, tc.wantAttrs, actual.Attributes)
+		}
+	}
+}
+
+func TestHandleLogEntry(t *testing.T) {
+	harvester, err := ingest.NewHarvester(ingest.ConfigAPIKey("api-key"), ingest.ConfigHarvestPeriod(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Handler{
+		harvester: harvester,
+		logs: map[string]info{
+			"accesslog.instance.istio-system": info{},
+		},
+	}
+
+	valid := []*logentry.InstanceMsg{{Name: "accesslog.instance.istio-system"}}
+	if err := h.HandleLogEntry(context.Background(), valid); err != nil {
+		t.Errorf("HandleLogEntry(%v) errored for valid input: %v", valid, err)
+	}
+
+	unknown := []*logentry.InstanceMsg{{Name: "not.found.log"}}
+	if err := h.HandleLogEntry(context.Background(), unknown); err == nil {
+		t.Errorf("HandleLogEntry(%v) did not error for unknown instance", unknown)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+// seriesKey identifies a metric with a particular attribute set.
+type seriesKey struct {
+	name  string
+	attrs uint64
+}
+
+// aggregator combines the values recorded for each metric and attribute
+// set between flushes into a single data point.
+type aggregator struct {
+	lock      sync.Mutex
+	start     time.Time
+	counts    map[seriesKey]*telemetry.Count
+	gauges    map[seriesKey]*telemetry.Gauge
+	summaries map[seriesKey]*telemetry.Summary
+}
+
+func newAggregator() *aggregator {
+	return &aggregator{
+		start:     time.Now(),
+		counts:    make(map[seriesKey]*telemetry.Count),
+		gauges:    make(map[seriesKey]*telemetry.Gauge),
+		summaries: make(map[seriesKey]*telemetry.Summary),
+	}
+}
+
+// count increases the count of the named metric with attrs by v.
+func (a *aggregator) count(name string, attrs map[string]interface{}, v float64) {
+	key := seriesKey{name, hashAttributes(attrs)}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	c, ok := a.counts[key]
+	if !ok {
+		c = &telemetry.Count{Name: name, Attributes: attrs}
+		a.counts[key] = c
+	}
+	c.Value += v
+}
+
+// gauge sets the value of the named metric with attrs to v. Only the last
+// value set between flushes is reported.
+func (a *aggregator) gauge(name string, attrs map[string]interface{}, v float64, now time.Time) {
+	key := seriesKey{name, hashAttributes(attrs)}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	g, ok := a.gauges[key]
+	if !ok {
+		g = &telemetry.Gauge{Name: name, Attributes: attrs}
+		a.gauges[key] = g
+	}
+	g.Value = v
+	g.Timestamp = now
+}
+
+// summary adds v to the summary of the named metric with attrs.
+func (a *aggregator) summary(name string, attrs map[string]interface{}, v float64) {
+	key := seriesKey{name, hashAttributes(attrs)}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	s, ok := a.summaries[key]
+	if !ok {
+		s = &telemetry.Summary{Name: name, Attributes: attrs, Min: v, Max: v}
+		a.summaries[key] = s
+	}
+	s.Count++
+	s.Sum += v
+	if v < s.Min {
+		s.Min = v
+	}
+	if v > s.Max {
+		s.Max = v
+	}
+}
+
+// swapOut returns a metric for each series recorded since the last call
+// and resets the aggregator. Counts and summaries cover the interval from
+// the last call until now.
+func (a *aggregator) swapOut(now time.Time) []telemetry.Metric {
+	a.lock.Lock()
+	start := a.start
+	a.start = now
+	counts, gauges, summaries := a.counts, a.gauges, a.summaries
+	a.counts = make(map[seriesKey]*telemetry.Count)
+	a.gauges = make(map[seriesKey]*telemetry.Gauge)
+	a.summaries = make(map[seriesKey]*telemetry.Summary)
+	a.lock.Unlock()
+
+	metrics := make([]telemetry.Metric, 0, len(counts)+len(gauges)+len(summaries))
+	for _, c := range counts {
+		c.Timestamp, c.Interval = start, now.Sub(start)
+		metrics = append(metrics, *c)
+	}
+	for _, g := range gauges {
+		metrics = append(metrics, *g)
+	}
+	for _, s := range summaries {
+		s.Timestamp, s.Interval = start, now.Sub(start)
+		metrics = append(metrics, *s)
+	}
+	return metrics
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+func TestAggregator(t *testing.T) {
+	a := newAggregator()
+	start := a.start
+	now := start.Add(5 * time.Second)
+	attrs := map[string]interface{}{"reporter": "source"}
+
+	a.count("request.total", attrs, 1)
+	a.count("request.total", attrs, 2)
+	a.gauge("request.bytes", attrs, 10, start)
+	a.gauge("request.bytes", attrs, 20, now)
+	a.summary("request.duration", attrs, 3)
+	a.summary("request.duration", attrs, 1)
+	a.summary("request.duration", attrs, 2)
+
+	expected := map[string]telemetry.Metric{
+		"request.total": telemetry.Count{
+			Name:       "request.total",
+			Attributes: attrs,
+			Value:      3,
+			Timestamp:  start,
+			Interval:   5 * time.Second,
+		},
+		"request.bytes": telemetry.Gauge{
+			Name:       "request.bytes",
+			Attributes: attrs,
+			Value:      20,
+			Timestamp:  now,
+		},
+		"request.duration": telemetry.Summary{
+			Name:       "request.duration",
+			Attributes: attrs,
+			Count:      3,
+			Sum:        6,
+			Min:        1,
+			Max:        3,
+			Timestamp:  start,
+			Interval:   5 * time.Second,
+		},
+	}
+
+	got := make(map[string]telemetry.Metric)
+	for _, m := range a.swapOut(now) {
+		switch v := m.(type) {
+		case telemetry.Count:
+			got[v.Name] = v
+		case telemetry.Gauge:
+			got[v.Name] = v
+		case telemetry.Summary:
+			got[v.Name] = v
+		}
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+
+	if m := a.swapOut(now.Add(time.Second)); len(m) != 0 {
+		t.Errorf("expected aggregator to be reset, got %#v", m)
+	}
+}
+
+func TestAggregatorAttributeSets(t *testing.T) {
+	a := newAggregator()
+	a.count("request.total", map[string]interface{}{"reporter": "source"}, 1)
+	a.count("request.total", map[string]interface{}{"reporter": "destination"}, 1)
+	a.count("request.total", map[string]interface{}{"reporter": "source"}, 1)
+
+	if m := a.swapOut(time.Now()); len(m) != 2 {
+		t.Errorf("expected a count for each attribute set, got %#v", m)
+	}
+}
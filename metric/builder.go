@@ -18,11 +18,13 @@ package metric
 import (
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf16"
 
 	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/convert"
+	"github.com/newrelic/newrelic-istio-adapter/export"
 	"github.com/newrelic/newrelic-istio-adapter/log"
-	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"istio.io/istio/mixer/pkg/adapter"
 )
 
@@ -30,15 +32,22 @@ const (
 	invalidNonEmptyErrMsg = "must be non-empty"
 	invalidTooLongErrMsg  = "must be <= 255 16-bit code units"
 	maxCodeUnitsCount     = 255
+
+	// maxBuckets is the most bucket boundaries a HISTOGRAM metric may
+	// have. Every attribute set of the metric holds a count per bucket.
+	maxBuckets = 200
 )
 
 type metricConfig struct {
-	namespace string
-	metrics   map[string]info
+	namespace        string
+	metrics          map[string]info
+	cardinalityLimit int
 }
 
-// BuildHandler returns a metric Handler with valid configuration.
-func BuildHandler(params *config.Params, h *telemetry.Harvester) (*Handler, error) {
+// BuildHandler returns a metric Handler with valid configuration. The
+// harvestPeriod is the rate aggregated metrics are recorded with e and the
+// window the metric cardinality limit applies to.
+func BuildHandler(params *config.Params, e export.Exporter, harvestPeriod time.Duration) (*Handler, error) {
 	cfg, err := buildConfig(params)
 	if err != nil {
 		return nil, err
@@ -46,8 +55,15 @@ func BuildHandler(params *config.Params, h *telemetry.Harvester) (*Handler, erro
 	log.Infof("built metrics: %#v", cfg.metrics)
 
 	handler := &Handler{
-		agg:     h.MetricAggregator(),
-		metrics: cfg.metrics,
+		exporter: e,
+		agg:      newAggregator(),
+		metrics:  cfg.metrics,
+		limiter:  newCardinalityLimiter(cfg.cardinalityLimit, harvestPeriod),
+
+		percentiles: newPercentileAggregator(),
+	}
+	if harvestPeriod > 0 {
+		handler.start(harvestPeriod)
 	}
 	return handler, nil
 }
@@ -59,8 +75,14 @@ func buildConfig(params *config.Params) (cfg *metricConfig, errs *adapter.Config
 	namespace := params.GetNamespace()
 	handlerMetrics := make(map[string]info, len(params.GetMetrics()))
 	cfg = &metricConfig{
-		namespace: namespace,
-		metrics:   handlerMetrics,
+		namespace:        namespace,
+		metrics:          handlerMetrics,
+		cardinalityLimit: int(params.GetMetricCardinalityLimit()),
+	}
+
+	if params.GetMetricCardinalityLimit() < 0 {
+		errs = errs.Appendf("metric_cardinality_limit", "must be >= 0")
+		cfg = nil
 	}
 
 	for instName, mInfo := range params.GetMetrics() {
@@ -69,6 +91,50 @@ func buildConfig(params *config.Params) (cfg *metricConfig, errs *adapter.Config
 			cfg = nil
 		}
 
+		for _, p := range mInfo.GetIncludeAttributes() {
+			if err := convert.ValidatePattern(p); err != nil {
+				errs = errs.Appendf("MetricInfo.include_attributes", "pattern %q is invalid: %v", p, err)
+				cfg = nil
+			}
+		}
+		for _, p := range mInfo.GetExcludeAttributes() {
+			if err := convert.ValidatePattern(p); err != nil {
+				errs = errs.Appendf("MetricInfo.exclude_attributes", "pattern %q is invalid: %v", p, err)
+				cfg = nil
+			}
+		}
+
+		buckets, bucketErrs := buildBuckets(mInfo)
+		for _, err := range bucketErrs {
+			errs = errs.Appendf("MetricInfo.buckets", "%v", err)
+			cfg = nil
+		}
+
+		for _, p := range mInfo.GetPercentiles() {
+			if p <= 0 || p > 100 {
+				errs = errs.Appendf("MetricInfo.percentiles", "percentile must be > 0 and <= 100: %v", p)
+				cfg = nil
+			}
+		}
+		if len(mInfo.GetPercentiles()) > 0 && mInfo.GetType() != config.SUMMARY {
+			errs = errs.Appendf("MetricInfo.percentiles", "percentiles are only valid for a SUMMARY type")
+			cfg = nil
+		}
+		if mInfo.GetSketchMaxBins() < 0 {
+			errs = errs.Appendf("MetricInfo.sketch_max_bins", "must be >= 0")
+			cfg = nil
+		}
+
+		var unit *convert.Unit
+		if mInfo.GetUnit() != "" {
+			u, err := convert.ParseUnit(mInfo.GetUnit())
+			if err != nil {
+				errs = errs.Appendf("MetricInfo.unit", "unit is invalid: %v", err)
+				cfg = nil
+			}
+			unit = &u
+		}
+
 		metricName := mInfo.GetName()
 		if metricName == "" {
 			errs = errs.Appendf("MetricInfo.name", "must be non-empty")
@@ -93,12 +159,79 @@ func buildConfig(params *config.Params) (cfg *metricConfig, errs *adapter.Config
 			cfg.metrics[instName] = info{
 				name:  fullName,
 				mtype: mInfo.GetType(),
+				attrs: convert.AttributeFilter{
+					Include: mInfo.GetIncludeAttributes(),
+					Exclude: mInfo.GetExcludeAttributes(),
+				},
+				buckets:       buckets,
+				percentiles:   mInfo.GetPercentiles(),
+				sketchMaxBins: int(mInfo.GetSketchMaxBins()),
+				unit:          unit,
 			}
 		}
 	}
 	return cfg, errs
 }
 
+// buildBuckets returns the histogram bucket boundaries configured for
+// mInfo and any errors found validating them.
+func buildBuckets(mInfo *config.Params_MetricInfo) ([]float64, []error) {
+	explicit := mInfo.GetBuckets()
+	exponential := mInfo.GetExponentialBuckets()
+
+	if mInfo.GetType() != config.HISTOGRAM {
+		if len(explicit) > 0 || exponential != nil {
+			return nil, []error{fmt.Errorf("buckets are only valid for a HISTOGRAM type")}
+		}
+		return nil, nil
+	}
+
+	switch {
+	case len(explicit) > 0 && exponential != nil:
+		return nil, []error{fmt.Errorf("only one of buckets or exponential_buckets may be specified")}
+	case len(explicit) > 0:
+		var errs []error
+		if len(explicit) > maxBuckets {
+			errs = append(errs, fmt.Errorf("buckets must have at most %d boundaries", maxBuckets))
+		}
+		for i := 1; i < len(explicit); i++ {
+			if explicit[i] <= explicit[i-1] {
+				errs = append(errs, fmt.Errorf("bucket boundaries must be strictly increasing: %v <= %v", explicit[i], explicit[i-1]))
+			}
+		}
+		if errs != nil {
+			return nil, errs
+		}
+		return explicit, nil
+	case exponential != nil:
+		var errs []error
+		if exponential.GetCount() <= 0 {
+			errs = append(errs, fmt.Errorf("exponential_buckets.count must be > 0"))
+		}
+		if exponential.GetCount() > maxBuckets {
+			errs = append(errs, fmt.Errorf("exponential_buckets.count must be <= %d", maxBuckets))
+		}
+		if exponential.GetStart() <= 0 {
+			errs = append(errs, fmt.Errorf("exponential_buckets.start must be > 0"))
+		}
+		if exponential.GetFactor() <= 1 {
+			errs = append(errs, fmt.Errorf("exponential_buckets.factor must be > 1"))
+		}
+		if errs != nil {
+			return nil, errs
+		}
+
+		buckets := make([]float64, exponential.GetCount())
+		bound := exponential.GetStart()
+		for i := range buckets {
+			buckets[i] = bound
+			bound *= exponential.GetFactor()
+		}
+		return buckets, nil
+	}
+	return nil, []error{fmt.Errorf("buckets or exponential_buckets must be specified for a HISTOGRAM type")}
+}
+
 func countUtf16CodeUnits(s string) int {
 	codePoints := []rune(s)
 	codeUnits := utf16.Encode(codePoints)
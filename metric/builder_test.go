@@ -15,6 +15,7 @@
 package metric
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -68,6 +69,209 @@ func TestBuildConfig(t *testing.T) {
 	}
 }
 
+func TestBuildConfigAttributePatterns(t *testing.T) {
+	testCases := []struct {
+		isValid        bool
+		include        []string
+		exclude        []string
+		expectedErrMsg string
+	}{
+		{true, nil, nil, ""},
+		{true, []string{"source.*", "response.code"}, []string{"request.path"}, ""},
+		{true, []string{"destination.?"}, []string{"*.ip", "[a-c]*"}, ""},
+		{false, []string{"source.["}, nil, "MetricInfo.include_attributes"},
+		{false, nil, []string{"request.[a-"}, "MetricInfo.exclude_attributes"},
+	}
+
+	for i, tc := range testCases {
+		pmi := &config.Params_MetricInfo{
+			Name:              "request.bytes",
+			Type:              config.GAUGE,
+			IncludeAttributes: tc.include,
+			ExcludeAttributes: tc.exclude,
+		}
+
+		params := &config.Params{
+			Metrics: map[string]*config.Params_MetricInfo{"example": pmi},
+		}
+
+		errString := ""
+		cfg, err := buildConfig(params)
+
+		if err != nil {
+			errString = err.Error()
+		}
+		if tc.isValid && err != nil {
+			t.Errorf("index %d: Did not expect error, got %s", i, errString)
+		}
+		if !tc.isValid {
+			if !strings.Contains(errString, tc.expectedErrMsg) {
+				t.Errorf("index %d: Expected error to contain '%s', got %s", i, tc.expectedErrMsg, errString)
+			}
+			if cfg != nil {
+				t.Errorf("index %d: Expected nil config for invalid pattern", i)
+			}
+		}
+	}
+}
+
+func TestBuildConfigBuckets(t *testing.T) {
+	testCases := []struct {
+		isValid         bool
+		mtype           config.Params_MetricInfo_Type
+		buckets         []float64
+		exponential     *config.Params_MetricInfo_ExponentialBuckets
+		expectedBuckets []float64
+		expectedErrMsg  string
+	}{
+		{true, config.HISTOGRAM, []float64{0.1, 1, 10}, nil, []float64{0.1, 1, 10}, ""},
+		{true, config.HISTOGRAM, nil, &config.Params_MetricInfo_ExponentialBuckets{Count: 4, Start: 1, Factor: 2}, []float64{1, 2, 4, 8}, ""},
+		{true, config.SUMMARY, nil, nil, nil, ""},
+		{false, config.HISTOGRAM, nil, nil, nil, "must be specified"},
+		{false, config.HISTOGRAM, []float64{1}, &config.Params_MetricInfo_ExponentialBuckets{Count: 1, Start: 1, Factor: 2}, nil, "only one of"},
+		{false, config.HISTOGRAM, []float64{1, 10, 10}, nil, nil, "strictly increasing"},
+		{false, config.HISTOGRAM, []float64{10, 1}, nil, nil, "strictly increasing"},
+		{false, config.HISTOGRAM, nil, &config.Params_MetricInfo_ExponentialBuckets{Count: 0, Start: 1, Factor: 2}, nil, "count must be > 0"},
+		{false, config.HISTOGRAM, nil, &config.Params_MetricInfo_ExponentialBuckets{Count: 2000000000, Start: 1, Factor: 2}, nil, "count must be <= 200"},
+		{false, config.HISTOGRAM, make([]float64, 201), nil, nil, "at most 200 boundaries"},
+		{false, config.HISTOGRAM, nil, &config.Params_MetricInfo_ExponentialBuckets{Count: 3, Start: 0, Factor: 2}, nil, "start must be > 0"},
+		{false, config.HISTOGRAM, nil, &config.Params_MetricInfo_ExponentialBuckets{Count: 3, Start: 1, Factor: 1}, nil, "factor must be > 1"},
+		{false, config.GAUGE, []float64{1, 2}, nil, nil, "only valid for a HISTOGRAM"},
+	}
+
+	for i, tc := range testCases {
+		pmi := &config.Params_MetricInfo{
+			Name:               "request.duration",
+			Type:               tc.mtype,
+			Buckets:            tc.buckets,
+			ExponentialBuckets: tc.exponential,
+		}
+
+		params := &config.Params{
+			Metrics: map[string]*config.Params_MetricInfo{"example": pmi},
+		}
+
+		cfg, err := buildConfig(params)
+		if tc.isValid {
+			if err != nil {
+				t.Errorf("index %d: Did not expect error, got %v", i, err)
+				continue
+			}
+			if !reflect.DeepEqual(cfg.metrics["example"].buckets, tc.expectedBuckets) {
+				t.Errorf("index %d: Expected buckets %v, got %v", i, tc.expectedBuckets, cfg.metrics["example"].buckets)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg) {
+			t.Errorf("index %d: Expected error to contain '%s', got %v", i, tc.expectedErrMsg, err)
+		}
+	}
+}
+
+func TestBuildConfigPercentiles(t *testing.T) {
+	testCases := []struct {
+		isValid        bool
+		mtype          config.Params_MetricInfo_Type
+		percentiles    []float64
+		maxBins        int32
+		expectedErrMsg string
+	}{
+		{true, config.SUMMARY, []float64{50, 90, 99, 99.9, 100}, 0, ""},
+		{true, config.SUMMARY, []float64{99}, 128, ""},
+		{false, config.SUMMARY, []float64{0}, 0, "percentile must be > 0"},
+		{false, config.SUMMARY, []float64{100.1}, 0, "percentile must be > 0"},
+		{false, config.GAUGE, []float64{50}, 0, "only valid for a SUMMARY"},
+		{false, config.SUMMARY, []float64{50}, -1, "MetricInfo.sketch_max_bins"},
+	}
+
+	for i, tc := range testCases {
+		pmi := &config.Params_MetricInfo{
+			Name:          "request.duration",
+			Type:          tc.mtype,
+			Percentiles:   tc.percentiles,
+			SketchMaxBins: tc.maxBins,
+		}
+
+		params := &config.Params{
+			Metrics: map[string]*config.Params_MetricInfo{"example": pmi},
+		}
+
+		_, err := buildConfig(params)
+		if tc.isValid && err != nil {
+			t.Errorf("index %d: Did not expect error, got %v", i, err)
+		}
+		if !tc.isValid && (err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg)) {
+			t.Errorf("index %d: Expected error to contain '%s', got %v", i, tc.expectedErrMsg, err)
+		}
+	}
+}
+
+func TestBuildConfigUnit(t *testing.T) {
+	testCases := []struct {
+		isValid bool
+		unit    string
+	}{
+		{true, ""},
+		{true, "ms"},
+		{true, "KiB"},
+		{false, "parsecs"},
+	}
+
+	for _, tc := range testCases {
+		pmi := &config.Params_MetricInfo{
+			Name: "request.duration",
+			Type: config.SUMMARY,
+			Unit: tc.unit,
+		}
+
+		params := &config.Params{
+			Metrics: map[string]*config.Params_MetricInfo{"example": pmi},
+		}
+
+		cfg, err := buildConfig(params)
+		if !tc.isValid {
+			if err == nil || !strings.Contains(err.Error(), "MetricInfo.unit") {
+				t.Errorf("unit %q: Expected MetricInfo.unit error, got %v", tc.unit, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unit %q: Did not expect error, got %v", tc.unit, err)
+			continue
+		}
+		u := cfg.metrics["example"].unit
+		if tc.unit == "" && u != nil {
+			t.Errorf("unit %q: Expected no unit, got %v", tc.unit, u)
+		}
+		if tc.unit != "" && (u == nil || u.String() != tc.unit) {
+			t.Errorf("unit %q: Expected unit %q, got %v", tc.unit, tc.unit, u)
+		}
+	}
+}
+
+func TestBuildConfigCardinalityLimit(t *testing.T) {
+	testCases := []struct {
+		isValid bool
+		limit   int32
+	}{
+		{true, 0},
+		{true, 1000},
+		{false, -1},
+	}
+
+	for _, tc := range testCases {
+		params := &config.Params{MetricCardinalityLimit: tc.limit}
+		cfg, err := buildConfig(params)
+		if tc.isValid {
+			if err != nil {
+				t.Errorf("limit %d: Did not expect error, got %v", tc.limit, err)
+			} else if cfg.cardinalityLimit != int(tc.limit) {
+				t.Errorf("limit %d: Expected config limit %d, got %d", tc.limit, tc.limit, cfg.cardinalityLimit)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), "metric_cardinality_limit") {
+			t.Errorf("limit %d: Expected metric_cardinality_limit error, got %v", tc.limit, err)
+		}
+	}
+}
+
 func TestBuildHandler(t *testing.T) {
 	mixerMetricName := "requestsize.instance.istio-system"
 
@@ -95,7 +299,7 @@ func TestBuildHandler(t *testing.T) {
 			Metrics:   map[string]*config.Params_MetricInfo{mixerMetricName: pmi},
 		}
 
-		h, err := BuildHandler(params, nil)
+		h, err := BuildHandler(params, nil, 0)
 
 		// Invalid name, but we saw expected error, so it's all good.
 		if !tc.isValid && err != nil {
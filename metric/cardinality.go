@@ -0,0 +1,111 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/log"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+)
+
+// OverflowAttribute is the attribute set on the series data points are
+// folded into once a metric reaches its cardinality limit.
+const OverflowAttribute = "overflow"
+
+// cardinalityLimiter tracks the distinct attribute sets of each metric
+// within a harvest window and folds data points with new attribute sets
+// into an overflow series once a metric has reached the limit. Folded data
+// points are counted by the MetricFolded self metric.
+type cardinalityLimiter struct {
+	limit  int
+	window time.Duration
+
+	lock        sync.Mutex
+	windowStart time.Time
+	series      map[string]map[uint64]struct{}
+	// warned holds the metrics a warning was logged for on reaching the
+	// limit.
+	warned map[string]bool
+}
+
+// newCardinalityLimiter returns a cardinalityLimiter allowing limit
+// attribute sets per metric each window. A limit of zero or less disables
+// the limiter. A window of zero never resets the tracked attribute sets.
+func newCardinalityLimiter(limit int, window time.Duration) *cardinalityLimiter {
+	return &cardinalityLimiter{
+		limit:       limit,
+		window:      window,
+		windowStart: time.Now(),
+		series:      make(map[string]map[uint64]struct{}),
+		warned:      make(map[string]bool),
+	}
+}
+
+// limitAttributes returns the attributes the data point for the named
+// metric should be recorded with. These are attrs unless the metric has
+// reached its limit and attrs is a new attribute set, in which case the
+// overflow attributes are returned.
+func (c *cardinalityLimiter) limitAttributes(name string, attrs map[string]interface{}, now time.Time) map[string]interface{} {
+	if c == nil || c.limit <= 0 {
+		return attrs
+	}
+	key := hashAttributes(attrs)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.window > 0 && now.Sub(c.windowStart) >= c.window {
+		c.series = make(map[string]map[uint64]struct{})
+		c.windowStart = now
+	}
+
+	seen, ok := c.series[name]
+	if !ok {
+		seen = make(map[uint64]struct{})
+		c.series[name] = seen
+	}
+	if _, ok := seen[key]; ok || len(seen) < c.limit {
+		seen[key] = struct{}{}
+		return attrs
+	}
+
+	if !c.warned[name] {
+		log.Warnf("metric %q reached cardinality limit of %d, folding new attribute sets into overflow series", name, c.limit)
+		c.warned[name] = true
+	}
+	selfmetric.RecordMetricFolded(name)
+	return map[string]interface{}{OverflowAttribute: true}
+}
+
+// hashAttributes returns a hash identifying the attribute set attrs
+// independent of map iteration order.
+func hashAttributes(attrs map[string]interface{}) uint64 {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := fnv.New64a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%v\x00", k, attrs[k])
+	}
+	return h.Sum64()
+}
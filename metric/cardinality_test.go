@@ -0,0 +1,108 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+// foldedCounts returns the data points folded for each metric as recorded
+// by the default self metric registry since it was last flushed.
+func foldedCounts() map[string]float64 {
+	r := &export.Recorder{}
+	selfmetric.Default().Flush(r)
+	counts := make(map[string]float64)
+	for _, m := range r.Metrics() {
+		if c, ok := m.(telemetry.Count); ok && c.Name == selfmetric.MetricFolded {
+			counts[c.Attributes["metric"].(string)] += c.Value
+		}
+	}
+	return counts
+}
+
+func TestCardinalityLimiter(t *testing.T) {
+	overflow := map[string]interface{}{OverflowAttribute: true}
+	attrs := func(path string) map[string]interface{} {
+		return map[string]interface{}{"request.path": path, "response.code": int64(200)}
+	}
+
+	c := newCardinalityLimiter(2, time.Minute)
+	start := c.windowStart
+	foldedCounts()
+
+	testCases := []struct {
+		metric   string
+		attrs    map[string]interface{}
+		now      time.Time
+		expected map[string]interface{}
+	}{
+		{"request.total", attrs("/a"), start, attrs("/a")},
+		{"request.total", attrs("/b"), start, attrs("/b")},
+		// limit reached, new attribute sets are folded
+		{"request.total", attrs("/c"), start, overflow},
+		{"request.total", attrs("/d"), start, overflow},
+		// attribute sets already seen are still recorded
+		{"request.total", attrs("/a"), start, attrs("/a")},
+		// limits are tracked per metric
+		{"request.bytes", attrs("/c"), start, attrs("/c")},
+		// a new window resets the tracked attribute sets
+		{"request.total", attrs("/c"), start.Add(time.Minute), attrs("/c")},
+	}
+
+	for i, tc := range testCases {
+		got := c.limitAttributes(tc.metric, tc.attrs, tc.now)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("index %d: expected %#v, got %#v", i, tc.expected, got)
+		}
+	}
+
+	expectedFolded := map[string]float64{"request.total": 2}
+	if got := foldedCounts(); !reflect.DeepEqual(got, expectedFolded) {
+		t.Errorf("expected folded counts %#v, got %#v", expectedFolded, got)
+	}
+}
+
+func TestCardinalityLimiterDisabled(t *testing.T) {
+	foldedCounts()
+	for _, c := range []*cardinalityLimiter{nil, newCardinalityLimiter(0, time.Minute)} {
+		for i := 0; i < 10; i++ {
+			attrs := map[string]interface{}{"request.id": i}
+			if got := c.limitAttributes("request.total", attrs, time.Now()); !reflect.DeepEqual(got, attrs) {
+				t.Errorf("expected %#v, got %#v", attrs, got)
+			}
+		}
+		if got := foldedCounts(); len(got) != 0 {
+			t.Errorf("expected no folded counts, got %#v", got)
+		}
+	}
+}
+
+func TestHashAttributesOrderIndependent(t *testing.T) {
+	a := map[string]interface{}{"a": 1, "b": "two", "c": true}
+	b := map[string]interface{}{"c": true, "a": 1, "b": "two"}
+	if hashAttributes(a) != hashAttributes(b) {
+		t.Error("expected equal attribute sets to hash equally")
+	}
+	b["c"] = false
+	if hashAttributes(a) == hashAttributes(b) {
+		t.Error("expected different attribute sets to hash differently")
+	}
+}
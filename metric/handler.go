@@ -19,22 +19,63 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/newrelic/newrelic-istio-adapter/config"
 	"github.com/newrelic/newrelic-istio-adapter/convert"
-	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+	policy "istio.io/api/policy/v1beta1"
 	"istio.io/istio/mixer/template/metric"
 )
 
+const (
+	// bucketSuffix is appended to the name of a HISTOGRAM metric to name
+	// the metric its bucket counts are reported as.
+	bucketSuffix = ".bucket"
+	// bucketAttribute is the attribute holding the upper boundary of the
+	// bucket a HISTOGRAM bucket count is for.
+	bucketAttribute = "le"
+	infBucket       = "+Inf"
+	// unitAttribute is the attribute holding the unit of a metric.
+	unitAttribute = "unit"
+)
+
 // Handler represents a processor that can handle metrics from Istio and transmit them to New Relic.
 type Handler struct {
-	agg     *telemetry.MetricAggregator
-	metrics map[string]info
+	exporter export.Exporter
+	agg      *aggregator
+	metrics  map[string]info
+	limiter  *cardinalityLimiter
+
+	percentiles *percentileAggregator
+	done        chan struct{}
+	closeOnce   sync.Once
+	wg          sync.WaitGroup
 }
 
 type info struct {
 	name  string
 	mtype config.Params_MetricInfo_Type
+	attrs convert.AttributeFilter
+	// buckets are the upper boundaries of HISTOGRAM buckets.
+	buckets []float64
+	// percentiles are the percentiles of SUMMARY values to report.
+	percentiles   []float64
+	sketchMaxBins int
+	// unit is the unit values are converted to, if any.
+	unit *convert.Unit
+}
+
+// value returns the metric value of in, converted to the unit of the
+// metric if it has one.
+func (i info) value(in *policy.Value) (float64, error) {
+	if i.unit != nil {
+		return convert.ValueToUnit(in, *i.unit)
+	}
+	return convert.ValueToFloat64(in)
 }
 
 type handleError struct {
@@ -73,34 +114,132 @@ func (e handleErrors) ErrorOrNil() error {
 func (h *Handler) HandleMetric(_ context.Context, msgs []*metric.InstanceMsg) error {
 	var errs handleErrors
 	for _, i := range msgs {
-		v, err := convert.ValueToFloat64(i.Value)
-		if err != nil {
-			errs = append(errs, &handleError{i.Name, err})
-			continue
-		}
-		attrs := convert.DimensionsToAttributes(i.Dimensions)
-
 		minfo, found := h.metrics[i.Name]
 		if !found {
+			selfmetric.RecordConversionError(selfmetric.MetricTemplate, "unknown_metric")
 			errs = append(errs, &handleError{i.Name, errors.New("no metric info found")})
 			continue
 		}
 
+		v, err := minfo.value(i.Value)
+		if err != nil {
+			selfmetric.RecordConversionError(selfmetric.MetricTemplate, "invalid_value")
+			errs = append(errs, &handleError{i.Name, err})
+			continue
+		}
+		attrs := minfo.attrs.Apply(convert.DimensionsToAttributes(i.Dimensions))
+		now := time.Now()
+		attrs = h.limiter.limitAttributes(minfo.name, attrs, now)
+		if minfo.unit != nil {
+			attrs[unitAttribute] = minfo.unit.String()
+		}
+
 		switch minfo.mtype {
 		case config.GAUGE:
-			h.agg.Gauge(minfo.name, attrs).Value(v)
+			h.agg.gauge(minfo.name, attrs, v, now)
 		case config.COUNT:
 			if v < 0.0 {
+				selfmetric.RecordConversionError(selfmetric.MetricTemplate, "negative_count")
 				errs = append(errs, &handleError{i.Name, fmt.Errorf("negative count value: %f", v)})
 				continue
 			}
-			h.agg.Count(minfo.name, attrs).Increase(v)
+			h.agg.count(minfo.name, attrs, v)
 		case config.SUMMARY:
-			h.agg.Summary(minfo.name, attrs).Record(v)
+			h.agg.summary(minfo.name, attrs, v)
+			if len(minfo.percentiles) > 0 {
+				h.percentiles.record(minfo, attrs, v)
+			}
+		case config.HISTOGRAM:
+			h.recordHistogram(minfo, attrs, v)
 		default:
+			selfmetric.RecordConversionError(selfmetric.MetricTemplate, "unknown_type")
 			errs = append(errs, &handleError{i.Name, fmt.Errorf("unknown metric type: %v", minfo.mtype)})
 		}
 	}
 
 	return errs.ErrorOrNil()
 }
+
+// RecordCount increases the count of the named metric with attrs by v,
+// subject to the cardinality limit of the metric.
+func (h *Handler) RecordCount(name string, attrs map[string]interface{}, v float64) {
+	h.agg.count(name, h.limiter.limitAttributes(name, attrs, time.Now()), v)
+}
+
+// RecordSummary adds v to the summary of the named metric with attrs,
+// subject to the cardinality limit of the metric.
+func (h *Handler) RecordSummary(name string, attrs map[string]interface{}, v float64) {
+	h.agg.summary(name, h.limiter.limitAttributes(name, attrs, time.Now()), v)
+}
+
+// start records the metrics aggregated since the last flush with the
+// exporter every period until the Handler is closed.
+func (h *Handler) start(period time.Duration) {
+	h.done = make(chan struct{})
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				h.Flush()
+			case <-h.done:
+				return
+			}
+		}
+	}()
+}
+
+// Flush records the metrics aggregated since the last flush, along with a
+// gauge for each configured percentile of the SUMMARY values, with the
+// exporter.
+func (h *Handler) Flush() {
+	now := time.Now()
+	for _, s := range h.percentiles.swapOut() {
+		for _, p := range s.percentiles {
+			h.agg.gauge(percentileName(s.name, p), s.attrs, s.sketch.quantile(p/100), now)
+		}
+	}
+	for _, m := range h.agg.swapOut(now) {
+		h.exporter.RecordMetric(m)
+	}
+}
+
+// Close stops the periodic flushing of aggregated metrics and records any
+// metrics not yet recorded with the exporter.
+func (h *Handler) Close() {
+	h.closeOnce.Do(func() {
+		if h.done != nil {
+			close(h.done)
+			h.wg.Wait()
+		}
+		h.Flush()
+	})
+}
+
+// recordHistogram records v in the cumulative bucket counts of the
+// HISTOGRAM described by minfo along with a summary of all values.
+func (h *Handler) recordHistogram(minfo info, attrs map[string]interface{}, v float64) {
+	h.agg.summary(minfo.name, attrs, v)
+
+	bucketName := minfo.name + bucketSuffix
+	for _, bound := range minfo.buckets {
+		if v <= bound {
+			h.agg.count(bucketName, bucketAttributes(attrs, strconv.FormatFloat(bound, 'g', -1, 64)), 1)
+		}
+	}
+	h.agg.count(bucketName, bucketAttributes(attrs, infBucket), 1)
+}
+
+// bucketAttributes returns a copy of attrs with the bucket boundary
+// attribute set to le.
+func bucketAttributes(attrs map[string]interface{}, le string) map[string]interface{} {
+	a := make(map[string]interface{}, len(attrs)+1)
+	for k, v := range attrs {
+		a[k] = v
+	}
+	a[bucketAttribute] = le
+	return a
+}
@@ -25,6 +25,8 @@ import (
 	"istio.io/istio/mixer/template/metric"
 
 	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/convert"
+	"github.com/newrelic/newrelic-istio-adapter/export"
 )
 
 func TestHandleMetric(t *testing.T) {
@@ -120,13 +122,9 @@ func TestHandleMetric(t *testing.T) {
 		},
 	}
 
-	harvester, err := telemetry.NewHarvester(telemetry.ConfigAPIKey("api-key"), telemetry.ConfigHarvestPeriod(0))
-	if err != nil {
-		t.Fatal(err)
-	}
-
 	metricHandler := &Handler{
-		agg: harvester.MetricAggregator(),
+		exporter: &export.Recorder{},
+		agg:      newAggregator(),
 		metrics: map[string]info{
 			"gaugeExample.instance.istio-system": info{
 				name:  "gaugeExample",
@@ -162,3 +160,187 @@ func TestHandleMetric(t *testing.T) {
 		}
 	}
 }
+
+// harvestedMetric is a metric as sent to the New Relic Metric API.
+type harvestedMetric struct {
+	Name       string
+	Type       string
+	Value      interface{}
+	Attributes map[string]interface{}
+}
+
+// harvestedMetrics returns the metrics recorded with r as they are sent to
+// the New Relic Metric API.
+func harvestedMetrics(t *testing.T, r *export.Recorder) []harvestedMetric {
+	var metrics []harvestedMetric
+	for _, m := range r.Metrics() {
+		switch v := m.(type) {
+		case telemetry.Count:
+			metrics = append(metrics, harvestedMetric{v.Name, "count", v.Value, v.Attributes})
+		case telemetry.Gauge:
+			metrics = append(metrics, harvestedMetric{v.Name, "gauge", v.Value, v.Attributes})
+		case telemetry.Summary:
+			value := map[string]interface{}{"count": v.Count, "sum": v.Sum, "min": v.Min, "max": v.Max}
+			metrics = append(metrics, harvestedMetric{v.Name, "summary", value, v.Attributes})
+		default:
+			t.Errorf("unexpected metric type %T", m)
+		}
+	}
+	return metrics
+}
+
+func TestHandleMetricHistogram(t *testing.T) {
+	recorder := &export.Recorder{}
+	metricHandler := &Handler{
+		exporter: recorder,
+		agg:      newAggregator(),
+		metrics: map[string]info{
+			"latency.instance.istio-system": info{
+				name:    "latency",
+				mtype:   config.HISTOGRAM,
+				buckets: []float64{0.1, 0.5, 1},
+			},
+		},
+	}
+
+	var msgs []*metric.InstanceMsg
+	for _, v := range []float64{0.05, 0.3, 0.5, 2} {
+		msgs = append(msgs, &metric.InstanceMsg{
+			Name:       "latency.instance.istio-system",
+			Value:      &policy.Value{Value: &policy.Value_DoubleValue{DoubleValue: v}},
+			Dimensions: map[string]*policy.Value{"reporter": {Value: &policy.Value_StringValue{StringValue: "source"}}},
+		})
+	}
+	if err := metricHandler.HandleMetric(context.Background(), msgs); err != nil {
+		t.Fatalf("HandleMetric errored: %v", err)
+	}
+	metricHandler.Flush()
+
+	expectedBuckets := map[string]float64{"0.1": 1, "0.5": 3, "1": 3, "+Inf": 4}
+	buckets := make(map[string]float64)
+	var summary *harvestedMetric
+	metrics := harvestedMetrics(t, recorder)
+	for i, m := range metrics {
+		switch m.Name {
+		case "latency.bucket":
+			if m.Type != "count" {
+				t.Errorf("expected bucket type count, got %q", m.Type)
+			}
+			if m.Attributes["reporter"] != "source" {
+				t.Errorf("expected bucket to keep attributes, got %v", m.Attributes)
+			}
+			buckets[m.Attributes[bucketAttribute].(string)] = m.Value.(float64)
+		case "latency":
+			summary = &metrics[i]
+		default:
+			t.Errorf("unexpected metric %q", m.Name)
+		}
+	}
+
+	if len(buckets) != len(expectedBuckets) {
+		t.Errorf("expected buckets %v, got %v", expectedBuckets, buckets)
+	}
+	for le, count := range expectedBuckets {
+		if buckets[le] != count {
+			t.Errorf("bucket %s: expected count %v, got %v", le, count, buckets[le])
+		}
+	}
+
+	if summary == nil {
+		t.Fatal("expected latency summary metric")
+	}
+	if summary.Type != "summary" {
+		t.Errorf("expected summary type, got %q", summary.Type)
+	}
+	if v, ok := summary.Value.(map[string]interface{}); !ok || v["count"] != float64(4) {
+		t.Errorf("expected summary of 4 values, got %v", summary.Value)
+	}
+}
+
+func TestHandleMetricPercentiles(t *testing.T) {
+	recorder := &export.Recorder{}
+	metricHandler := &Handler{
+		exporter: recorder,
+		agg:      newAggregator(),
+		metrics: map[string]info{
+			"duration.instance.istio-system": info{
+				name:        "duration",
+				mtype:       config.SUMMARY,
+				percentiles: []float64{50, 99.9},
+			},
+		},
+		percentiles: newPercentileAggregator(),
+	}
+
+	var msgs []*metric.InstanceMsg
+	for i := 1; i <= 100; i++ {
+		msgs = append(msgs, &metric.InstanceMsg{
+			Name:       "duration.instance.istio-system",
+			Value:      &policy.Value{Value: &policy.Value_Int64Value{Int64Value: int64(i)}},
+			Dimensions: map[string]*policy.Value{},
+		})
+	}
+	if err := metricHandler.HandleMetric(context.Background(), msgs); err != nil {
+		t.Fatalf("HandleMetric errored: %v", err)
+	}
+	metricHandler.Close()
+
+	expected := map[string]float64{"duration.p50": 50, "duration.p99.9": 99}
+	got := make(map[string]float64)
+	for _, m := range harvestedMetrics(t, recorder) {
+		if m.Type == "gauge" {
+			got[m.Name] = m.Value.(float64)
+		}
+	}
+	if len(got) != len(expected) {
+		t.Errorf("expected percentile gauges %v, got %v", expected, got)
+	}
+	for name, v := range expected {
+		if g, ok := got[name]; !ok || g < v*(1-sketchRelativeAccuracy) || g > v*(1+sketchRelativeAccuracy) {
+			t.Errorf("%s: expected %v, got %v", name, v, g)
+		}
+	}
+}
+
+func TestHandleMetricUnit(t *testing.T) {
+	recorder := &export.Recorder{}
+	seconds, err := convert.ParseUnit("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metricHandler := &Handler{
+		exporter: recorder,
+		agg:      newAggregator(),
+		metrics: map[string]info{
+			"duration.instance.istio-system": info{
+				name:  "duration",
+				mtype: config.GAUGE,
+				unit:  &seconds,
+			},
+		},
+	}
+
+	msgs := []*metric.InstanceMsg{{
+		Name: "duration.instance.istio-system",
+		Value: &policy.Value{Value: &policy.Value_DurationValue{DurationValue: &policy.Duration{
+			Value: types.DurationProto(1500 * time.Millisecond),
+		}}},
+		Dimensions: map[string]*policy.Value{},
+	}}
+	if err := metricHandler.HandleMetric(context.Background(), msgs); err != nil {
+		t.Fatalf("HandleMetric errored: %v", err)
+	}
+	metricHandler.Flush()
+
+	metrics := harvestedMetrics(t, recorder)
+	if len(metrics) != 1 {
+		t.Fatalf("expected 1 metric, got %d", len(metrics))
+	}
+	m := metrics[0]
+	if m.Value != 1.5 {
+		t.Errorf("expected value 1.5, got %v", m.Value)
+	}
+	if m.Attributes[unitAttribute] != "s" {
+		t.Errorf("expected unit attribute %q, got %v", "s", m.Attributes[unitAttribute])
+	}
+}
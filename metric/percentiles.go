@@ -0,0 +1,95 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"strconv"
+	"sync"
+)
+
+// percentileSeries is the sketch of the values of a metric with a
+// particular attribute set.
+type percentileSeries struct {
+	name        string
+	attrs       map[string]interface{}
+	percentiles []float64
+	sketch      *sketch
+}
+
+// percentileAggregator keeps a sketch for each metric and attribute set
+// that percentiles are computed from.
+type percentileAggregator struct {
+	lock   sync.Mutex
+	series map[string]map[uint64]*percentileSeries
+}
+
+func newPercentileAggregator() *percentileAggregator {
+	return &percentileAggregator{
+		series: make(map[string]map[uint64]*percentileSeries),
+	}
+}
+
+// record adds v to the sketch of the metric described by minfo with attrs.
+func (p *percentileAggregator) record(minfo info, attrs map[string]interface{}, v float64) {
+	if p == nil {
+		return
+	}
+	key := hashAttributes(attrs)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	byAttrs, ok := p.series[minfo.name]
+	if !ok {
+		byAttrs = make(map[uint64]*percentileSeries)
+		p.series[minfo.name] = byAttrs
+	}
+	s, ok := byAttrs[key]
+	if !ok {
+		s = &percentileSeries{
+			name:        minfo.name,
+			attrs:       attrs,
+			percentiles: minfo.percentiles,
+			sketch:      newSketch(minfo.sketchMaxBins),
+		}
+		byAttrs[key] = s
+	}
+	s.sketch.add(v)
+}
+
+// swapOut returns all recorded series and resets the aggregator.
+func (p *percentileAggregator) swapOut() []*percentileSeries {
+	if p == nil {
+		return nil
+	}
+	p.lock.Lock()
+	series := p.series
+	p.series = make(map[string]map[uint64]*percentileSeries)
+	p.lock.Unlock()
+
+	var out []*percentileSeries
+	for _, byAttrs := range series {
+		for _, s := range byAttrs {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// percentileName returns the name of the gauge metric reporting the
+// percentile p of the metric name, e.g. `request.duration.p99`.
+func percentileName(name string, p float64) string {
+	return name + ".p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
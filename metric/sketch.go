@@ -0,0 +1,155 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// sketchRelativeAccuracy is the relative accuracy of quantiles
+	// computed from a sketch that has not collapsed any bins.
+	sketchRelativeAccuracy = 0.01
+	// defaultSketchMaxBins is the default maximum number of bins kept by
+	// a sketch for each sign of the values it records.
+	defaultSketchMaxBins = 1024
+)
+
+var (
+	sketchGamma    = (1 + sketchRelativeAccuracy) / (1 - sketchRelativeAccuracy)
+	sketchLogGamma = math.Log(sketchGamma)
+)
+
+// sketch is a DDSketch: a quantile sketch with relative error guarantees.
+// Values are counted in logarithmically sized bins. The number of bins is
+// bounded by collapsing the bins of the smallest magnitude values
+// together, which only reduces the accuracy of the lowest quantiles.
+type sketch struct {
+	maxBins  int
+	positive map[int]uint64
+	negative map[int]uint64
+	zeros    uint64
+	count    uint64
+	min, max float64
+}
+
+func newSketch(maxBins int) *sketch {
+	if maxBins <= 0 {
+		maxBins = defaultSketchMaxBins
+	}
+	return &sketch{
+		maxBins:  maxBins,
+		positive: make(map[int]uint64),
+		negative: make(map[int]uint64),
+		min:      math.Inf(1),
+		max:      math.Inf(-1),
+	}
+}
+
+// sketchKey returns the key of the bin v (> 0) is counted in.
+func sketchKey(v float64) int {
+	return int(math.Ceil(math.Log(v) / sketchLogGamma))
+}
+
+// sketchValue returns the value representative of the bin with key k.
+func sketchValue(k int) float64 {
+	return 2 * math.Pow(sketchGamma, float64(k)) / (sketchGamma + 1)
+}
+
+// add records v in the sketch.
+func (s *sketch) add(v float64) {
+	switch {
+	case v > 0:
+		s.positive[sketchKey(v)]++
+		collapse(s.positive, s.maxBins)
+	case v < 0:
+		s.negative[sketchKey(-v)]++
+		collapse(s.negative, s.maxBins)
+	default:
+		s.zeros++
+	}
+	s.count++
+	s.min = math.Min(s.min, v)
+	s.max = math.Max(s.max, v)
+}
+
+// collapse merges the lowest bins of bins until it holds at most maxBins.
+func collapse(bins map[int]uint64, maxBins int) {
+	if len(bins) <= maxBins {
+		return
+	}
+	keys := sortedKeys(bins)
+	for len(bins) > maxBins {
+		lowest, next := keys[0], keys[1]
+		bins[next] += bins[lowest]
+		delete(bins, lowest)
+		keys = keys[1:]
+	}
+}
+
+func sortedKeys(bins map[int]uint64) []int {
+	keys := make([]int, 0, len(bins))
+	for k := range bins {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// quantile returns an estimate of the q quantile (0 <= q <= 1) of the
+// values recorded in the sketch. It returns NaN if the sketch is empty.
+func (s *sketch) quantile(q float64) float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return s.min
+	}
+	if q >= 1 {
+		return s.max
+	}
+
+	rank := uint64(q * float64(s.count-1))
+	var seen uint64
+
+	// Negative values in increasing order are the negative bins in
+	// decreasing key order.
+	negKeys := sortedKeys(s.negative)
+	for i := len(negKeys) - 1; i >= 0; i-- {
+		seen += s.negative[negKeys[i]]
+		if seen > rank {
+			return s.clamp(-sketchValue(negKeys[i]))
+		}
+	}
+
+	seen += s.zeros
+	if seen > rank {
+		return 0
+	}
+
+	for _, k := range sortedKeys(s.positive) {
+		seen += s.positive[k]
+		if seen > rank {
+			return s.clamp(sketchValue(k))
+		}
+	}
+	return s.max
+}
+
+// clamp limits v to the range of recorded values.
+func (s *sketch) clamp(v float64) float64 {
+	return math.Max(s.min, math.Min(s.max, v))
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSketchQuantile(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values func(i int) float64
+		count  int
+	}{
+		{"positive", func(i int) float64 { return float64(i + 1) }, 1000},
+		{"negative", func(i int) float64 { return -float64(i + 1) }, 1000},
+		{"mixed", func(i int) float64 { return float64(i - 500) }, 1001},
+		{"fractional", func(i int) float64 { return float64(i+1) / 1000 }, 1000},
+	}
+
+	for _, tc := range testCases {
+		s := newSketch(0)
+		values := make([]float64, tc.count)
+		for i := range values {
+			values[i] = tc.values(i)
+			s.add(values[i])
+		}
+		// values are generated in ascending or descending order
+		if values[0] > values[len(values)-1] {
+			for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+				values[i], values[j] = values[j], values[i]
+			}
+		}
+
+		for _, q := range []float64{0, 0.5, 0.9, 0.99, 1} {
+			expected := values[int(q*float64(len(values)-1))]
+			got := s.quantile(q)
+			if math.Abs(got-expected) > math.Abs(expected)*sketchRelativeAccuracy+1e-9 {
+				t.Errorf("%s: q%v expected %v within %v relative error, got %v", tc.name, q, expected, sketchRelativeAccuracy, got)
+			}
+		}
+	}
+}
+
+func TestSketchEmpty(t *testing.T) {
+	if q := newSketch(0).quantile(0.5); !math.IsNaN(q) {
+		t.Errorf("expected NaN from empty sketch, got %v", q)
+	}
+}
+
+func TestSketchMaxBins(t *testing.T) {
+	s := newSketch(10)
+	for i := 1; i <= 10000; i++ {
+		s.add(float64(i))
+	}
+	if len(s.positive) > 10 {
+		t.Errorf("expected at most 10 bins, got %d", len(s.positive))
+	}
+	// the highest quantiles keep their accuracy
+	if got := s.quantile(0.99); math.Abs(got-9900) > 9900*sketchRelativeAccuracy {
+		t.Errorf("expected q0.99 near 9900, got %v", got)
+	}
+	if got := s.quantile(1); got != 10000 {
+		t.Errorf("expected max 10000, got %v", got)
+	}
+}
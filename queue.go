@@ -0,0 +1,261 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelic
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/newrelic/newrelic-istio-adapter/log"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// QueuePolicy is what is done with a request when the ingestion queue is
+// full.
+type QueuePolicy string
+
+// Policies applied when the ingestion queue is full.
+const (
+	// DropOldest drops the oldest queued request to make room for the new
+	// one.
+	DropOldest QueuePolicy = "drop-oldest"
+	// DropNewest drops the new request.
+	DropNewest QueuePolicy = "drop-newest"
+	// Reject returns a ResourceExhausted error to Mixer for the new
+	// request.
+	Reject QueuePolicy = "reject"
+)
+
+// QueuePolicies returns the valid QueuePolicy values.
+func QueuePolicies() []string {
+	return []string{string(DropOldest), string(DropNewest), string(Reject)}
+}
+
+// QueueConfig configures the queue the instances received from Mixer are
+// handled from.
+type QueueConfig struct {
+	// Capacity is the maximum number of requests queued. Zero or less
+	// disables the queue and requests are handled as they are received.
+	Capacity int
+	// Workers is the number of requests handled concurrently. Zero or
+	// less means one.
+	Workers int
+	// Policy is applied to requests received while the queue is full.
+	Policy QueuePolicy
+	// OnSaturated, if set, is called with true when the queue becomes
+	// full and with false once it has drained to half its capacity.
+	OnSaturated func(saturated bool)
+}
+
+// QueueStats are the counts of the ingestion queue since the Server was
+// created.
+type QueueStats struct {
+	// Depth is the number of requests currently queued.
+	Depth int
+	// Capacity is the maximum number of requests queued.
+	Capacity int
+	// Dropped is the number of requests dropped or rejected because the
+	// queue was full or the Server was closed.
+	Dropped uint64
+}
+
+// errQueueFull is returned to Mixer for requests rejected because the
+// queue is full.
+var errQueueFull = status.Error(codes.ResourceExhausted, "ingestion queue is full")
+
+// job handles the instances of a request.
+type job struct {
+	template  string
+	instances int
+	handle    func(ctx context.Context) error
+	// done, if set, is called once the job is handled or dropped.
+	done func()
+}
+
+// finish calls the done callback of j.
+func (j job) finish() {
+	if j.done != nil {
+		j.done()
+	}
+}
+
+// ingestQueue is a bounded queue of requests handled by a pool of
+// workers.
+type ingestQueue struct {
+	cfg  QueueConfig
+	jobs chan job
+
+	// lock guards sending to jobs against closing it.
+	lock   sync.RWMutex
+	closed bool
+
+	saturatedLock sync.Mutex
+	saturated     bool
+
+	// dropped is accessed atomically.
+	dropped uint64
+
+	// abandon is closed to drop the queued requests instead of handling
+	// them.
+	abandon chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newIngestQueue(cfg QueueConfig) *ingestQueue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	q := &ingestQueue{
+		cfg:     cfg,
+		jobs:    make(chan job, cfg.Capacity),
+		abandon: make(chan struct{}),
+	}
+	q.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go q.work()
+	}
+	q.recordDepth()
+	return q
+}
+
+// push queues j, applying the policy if the queue is full. It returns an
+// error if j was rejected.
+func (q *ingestQueue) push(j job) error {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	if q.closed {
+		q.drop(j, "closed")
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+
+	for {
+		select {
+		case q.jobs <- j:
+			q.recordDepth()
+			return nil
+		default:
+		}
+
+		q.setSaturated(true)
+		switch q.cfg.Policy {
+		case DropNewest:
+			q.drop(j, string(DropNewest))
+			return nil
+		case DropOldest:
+			select {
+			case old := <-q.jobs:
+				q.drop(old, string(DropOldest))
+			default:
+			}
+		default:
+			q.drop(j, string(Reject))
+			return errQueueFull
+		}
+	}
+}
+
+// drop records j was dropped for reason.
+func (q *ingestQueue) drop(j job, reason string) {
+	atomic.AddUint64(&q.dropped, 1)
+	selfmetric.RecordQueueDropped(j.template, reason, j.instances)
+	j.finish()
+}
+
+// setSaturated records whether the queue is saturated, notifying
+// OnSaturated if it changed.
+func (q *ingestQueue) setSaturated(saturated bool) {
+	q.saturatedLock.Lock()
+	defer q.saturatedLock.Unlock()
+	if q.saturated == saturated {
+		return
+	}
+	q.saturated = saturated
+	if saturated {
+		log.Warnf("ingestion queue is full, applying the %s policy", q.cfg.Policy)
+	}
+	if q.cfg.OnSaturated != nil {
+		q.cfg.OnSaturated(saturated)
+	}
+}
+
+func (q *ingestQueue) recordDepth() {
+	selfmetric.RecordQueueDepth(len(q.jobs), q.cfg.Capacity)
+}
+
+// work handles queued requests until the queue is closed.
+func (q *ingestQueue) work() {
+	defer q.wg.Done()
+	for j := range q.jobs {
+		depth := len(q.jobs)
+		q.recordDepth()
+		if depth <= q.cfg.Capacity/2 {
+			q.setSaturated(false)
+		}
+
+		select {
+		case <-q.abandon:
+			q.drop(j, "closed")
+			continue
+		default:
+		}
+
+		err := j.handle(context.Background())
+		j.finish()
+		if err != nil {
+			log.Errorf("failed to handle %s: %v", j.template, err)
+		}
+	}
+}
+
+// stats returns the counts of the queue.
+func (q *ingestQueue) stats() QueueStats {
+	return QueueStats{
+		Depth:    len(q.jobs),
+		Capacity: q.cfg.Capacity,
+		Dropped:  atomic.LoadUint64(&q.dropped),
+	}
+}
+
+// close stops queueing requests and waits for the queued ones to be
+// handled. Once ctx is done the remaining requests are dropped.
+func (q *ingestQueue) close(ctx context.Context) {
+	q.lock.Lock()
+	if q.closed {
+		q.lock.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.jobs)
+	q.lock.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		if n := len(q.jobs); n > 0 {
+			log.Warnf("shutdown deadline exceeded, dropping %d queued requests", n)
+		}
+		close(q.abandon)
+		<-drained
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelic
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/metric"
+)
+
+func TestQueuePolicies(t *testing.T) {
+	testCases := []struct {
+		policy   QueuePolicy
+		expected []string
+		errCode  codes.Code
+	}{
+		{DropNewest, []string{"first", "queued"}, codes.OK},
+		{DropOldest, []string{"first", "new"}, codes.OK},
+		{Reject, []string{"first", "queued"}, codes.ResourceExhausted},
+	}
+
+	for _, tc := range testCases {
+		var lock sync.Mutex
+		var handled []string
+		var saturated []bool
+		q := newIngestQueue(QueueConfig{
+			Capacity: 1,
+			Policy:   tc.policy,
+			OnSaturated: func(s bool) {
+				lock.Lock()
+				defer lock.Unlock()
+				saturated = append(saturated, s)
+			},
+		})
+
+		started, unblock := make(chan struct{}), make(chan struct{})
+		record := func(name string) job {
+			return job{template: "metric", instances: 1, handle: func(context.Context) error {
+				lock.Lock()
+				handled = append(handled, name)
+				lock.Unlock()
+				if name == "first" {
+					close(started)
+					<-unblock
+				}
+				return nil
+			}}
+		}
+
+		// The worker blocks on the first request so the queue fills up.
+		if err := q.push(record("first")); err != nil {
+			t.Fatalf("%s: push errored: %v", tc.policy, err)
+		}
+		<-started
+		if err := q.push(record("queued")); err != nil {
+			t.Fatalf("%s: push errored: %v", tc.policy, err)
+		}
+		err := q.push(record("new"))
+		if code := status.Code(err); code != tc.errCode {
+			t.Errorf("%s: expected error code %v, got %v", tc.policy, tc.errCode, err)
+		}
+		if stats := q.stats(); stats.Depth != 1 || stats.Dropped != 1 {
+			t.Errorf("%s: expected 1 queued and 1 dropped request, got %+v", tc.policy, stats)
+		}
+
+		close(unblock)
+		q.close(context.Background())
+
+		lock.Lock()
+		if len(handled) != len(tc.expected) || handled[0] != tc.expected[0] || handled[1] != tc.expected[1] {
+			t.Errorf("%s: expected %v handled, got %v", tc.policy, tc.expected, handled)
+		}
+		if len(saturated) != 2 || !saturated[0] || saturated[1] {
+			t.Errorf("%s: expected queue saturated then drained, got %v", tc.policy, saturated)
+		}
+		lock.Unlock()
+	}
+}
+
+func TestQueueCloseDeadline(t *testing.T) {
+	q := newIngestQueue(QueueConfig{Capacity: 10})
+
+	// The worker is blocked until the queued requests are abandoned.
+	started := make(chan struct{})
+	handled := 0
+	// Handled and dropped requests are both done.
+	var done int32
+	finish := func() { atomic.AddInt32(&done, 1) }
+	q.push(job{template: "metric", handle: func(context.Context) error {
+		close(started)
+		<-q.abandon
+		return nil
+	}, done: finish})
+	<-started
+	for i := 0; i < 3; i++ {
+		q.push(job{template: "metric", handle: func(context.Context) error {
+			handled++
+			return nil
+		}, done: finish})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	q.close(ctx)
+
+	if handled != 0 {
+		t.Errorf("expected queued requests dropped once the deadline passed, %d were handled", handled)
+	}
+	if stats := q.stats(); stats.Dropped != 3 {
+		t.Errorf("expected 3 dropped requests, got %+v", stats)
+	}
+	if n := atomic.LoadInt32(&done); n != 4 {
+		t.Errorf("expected 4 requests done, got %d", n)
+	}
+	if err := q.push(job{template: "metric"}); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected requests refused once closed, got %v", err)
+	}
+}
+
+func TestCloseHandlesQueuedRequests(t *testing.T) {
+	recorder := &export.Recorder{}
+	build := func(Account) (export.Exporter, *ingest.Harvester, error) {
+		return recorder, nil, nil
+	}
+	s, err := NewServer(":0", Account{}, SecretConfig{}, build, 0, HandlerCacheConfig{}, QueueConfig{Capacity: 10, Workers: 2})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	rawcfg, err := (&config.Params{
+		Metrics: map[string]*config.Params_MetricInfo{
+			"requests.instance.istio-system": {Name: "requests", Type: config.GAUGE},
+		},
+	}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		_, err := s.HandleMetric(context.Background(), &metric.HandleMetricRequest{
+			AdapterConfig: &types.Any{Value: rawcfg},
+			Instances: []*metric.InstanceMsg{{
+				Name:       "requests.instance.istio-system",
+				Value:      &policy.Value{Value: &policy.Value_Int64Value{Int64Value: int64(i)}},
+				Dimensions: map[string]*policy.Value{},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("HandleMetric errored: %v", err)
+		}
+	}
+
+	if err := s.Close(context.Background()); err != nil {
+		t.Errorf("Close errored: %v", err)
+	}
+	gauges := 0
+	for _, m := range recorder.Metrics() {
+		if g, ok := m.(telemetry.Gauge); ok && g.Name == "requests" {
+			gauges++
+		}
+	}
+	if gauges != 1 {
+		t.Errorf("expected queued requests handled before Close returned, got %d requests gauges", gauges)
+	}
+}
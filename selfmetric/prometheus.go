@@ -0,0 +1,161 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfmetric
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// promNamespace prefixes the names of the Prometheus metrics.
+const promNamespace = "newrelic_istio_adapter"
+
+// The Prometheus metrics are updated along with the self metrics, and are
+// cumulative rather than reset each harvest period.
+var (
+	promRegistry = prometheus.NewRegistry()
+
+	promBuildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "build_info",
+		Help:      "Always 1, labeled with the adapter version.",
+	}, []string{"version"})
+	promRPCs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "rpcs_total",
+		Help:      "Mixer RPCs handled, by template and status.",
+	}, []string{"template", "status"})
+	promRPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNamespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Time taken to handle Mixer RPCs, by template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"template"})
+	promInstances = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "instances_received_total",
+		Help:      "Instances received, by template.",
+	}, []string{"template"})
+	promConversionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "conversion_errors_total",
+		Help:      "Instances dropped because they could not be converted, by template and reason.",
+	}, []string{"template", "reason"})
+	promHandlerBuilds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "handler_builds_total",
+		Help:      "Handlers built for handler configurations, by whether the configuration had been built before.",
+	}, []string{"rebuild"})
+	promHarvestRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "harvest_requests_total",
+		Help:      "Posts to New Relic, by endpoint and response status or error.",
+	}, []string{"endpoint", "status"})
+	promHarvestPayloadBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNamespace,
+		Name:      "harvest_payload_bytes",
+		Help:      "Size of the payloads posted to New Relic, by endpoint.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 8),
+	}, []string{"endpoint"})
+	promSpoolEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "spool_entries",
+		Help:      "Payloads waiting in the spool.",
+	})
+	promSpoolBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "spool_bytes",
+		Help:      "Size of the payloads waiting in the spool.",
+	})
+	promSpoolOldestAge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "spool_oldest_age_seconds",
+		Help:      "Age of the oldest payload waiting in the spool.",
+	})
+	promSpoolReplayed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "spool_replayed_total",
+		Help:      "Spooled payloads sent to New Relic.",
+	})
+	promSpoolDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "spool_dropped_total",
+		Help:      "Spooled payloads dropped without being sent, by reason.",
+	}, []string{"reason"})
+	promQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "queue_depth",
+		Help:      "Requests waiting in the ingestion queue.",
+	})
+	promQueueCapacity = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "queue_capacity",
+		Help:      "Maximum requests waiting in the ingestion queue.",
+	})
+	promQueueDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "queue_dropped_instances_total",
+		Help:      "Instances dropped from the ingestion queue, by template and reason.",
+	}, []string{"template", "reason"})
+	promMetricFolded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "metric_folded_total",
+		Help:      "Data points folded into the overflow series of metrics that reached their cardinality limit, by metric.",
+	}, []string{"metric"})
+	promSpansDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "spans_dropped_total",
+		Help:      "Spans deliberately not sent to New Relic, by reason.",
+	}, []string{"reason"})
+	promSpansRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespace,
+		Name:      "spans_rate_limited_total",
+		Help:      "Spans dropped for exceeding the rate limit of their service, by service with its own limit or other.",
+	}, []string{"service"})
+)
+
+func init() {
+	promRegistry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		promBuildInfo,
+		promRPCs,
+		promRPCDuration,
+		promInstances,
+		promConversionErrors,
+		promHandlerBuilds,
+		promHarvestRequests,
+		promHarvestPayloadBytes,
+		promSpoolEntries,
+		promSpoolBytes,
+		promSpoolOldestAge,
+		promSpoolReplayed,
+		promSpoolDropped,
+		promQueueDepth,
+		promQueueCapacity,
+		promQueueDropped,
+		promMetricFolded,
+		promSpansDropped,
+		promSpansRateLimited,
+	)
+}
+
+// PrometheusHandler returns an http.Handler serving the self metrics in
+// the Prometheus exposition format.
+func PrometheusHandler() http.Handler {
+	return promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfmetric
+
+import (
+	"strconv"
+	"time"
+)
+
+// Templates of the instances the adapter handles.
+const (
+	MetricTemplate    = "metric"
+	TraceSpanTemplate = "tracespan"
+	LogEntryTemplate  = "logentry"
+	EdgeTemplate      = "edge"
+)
+
+// SetVersion sets the adapter version self metrics are tagged with.
+func SetVersion(version string) {
+	defaultRegistry.SetVersion(version)
+	promBuildInfo.WithLabelValues(version).Set(1)
+}
+
+// RecordInstancesReceived records n instances of template were received.
+func RecordInstancesReceived(template string, n int) {
+	defaultRegistry.Count(InstancesReceived, float64(n), "template", template)
+	promInstances.WithLabelValues(template).Add(float64(n))
+}
+
+// RecordConversionError records an instance of template was dropped
+// because it could not be converted for reason.
+func RecordConversionError(template, reason string) {
+	defaultRegistry.Count(ConversionErrors, 1, "template", template, "reason", reason)
+	promConversionErrors.WithLabelValues(template, reason).Inc()
+}
+
+// RecordHandlerBuild records a handler was built for a configuration.
+// Rebuild is whether a handler had been built for the configuration
+// before.
+func RecordHandlerBuild(rebuild bool) {
+	defaultRegistry.Count(HandlerBuilds, 1, "rebuild", strconv.FormatBool(rebuild))
+	promHandlerBuilds.WithLabelValues(strconv.FormatBool(rebuild)).Inc()
+}
+
+// RecordRPC records handling a Mixer RPC for template took d and whether
+// it failed.
+func RecordRPC(template string, d time.Duration, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	defaultRegistry.Summary(RPCDuration, d.Seconds(), "template", template, "status", status)
+	promRPCs.WithLabelValues(template, status).Inc()
+	promRPCDuration.WithLabelValues(template).Observe(d.Seconds())
+}
+
+// RecordSpoolDepth records the spool holds entries payloads of size bytes,
+// the oldest of which was spooled age ago.
+func RecordSpoolDepth(entries int, size int64, age time.Duration) {
+	defaultRegistry.Gauge(SpoolEntries, float64(entries))
+	defaultRegistry.Gauge(SpoolBytes, float64(size))
+	defaultRegistry.Gauge(SpoolOldestAge, age.Seconds())
+	promSpoolEntries.Set(float64(entries))
+	promSpoolBytes.Set(float64(size))
+	promSpoolOldestAge.Set(age.Seconds())
+}
+
+// RecordSpoolReplayed records a spooled payload was sent to New Relic.
+func RecordSpoolReplayed() {
+	defaultRegistry.Count(SpoolReplayed, 1)
+	promSpoolReplayed.Inc()
+}
+
+// RecordSpoolDropped records n spooled payloads were dropped for reason.
+func RecordSpoolDropped(reason string, n int) {
+	defaultRegistry.Count(SpoolDropped, float64(n), "reason", reason)
+	promSpoolDropped.WithLabelValues(reason).Add(float64(n))
+}
+
+// RecordQueueDepth records depth requests are waiting in the ingestion
+// queue of capacity.
+func RecordQueueDepth(depth, capacity int) {
+	defaultRegistry.Gauge(QueueDepth, float64(depth))
+	promQueueDepth.Set(float64(depth))
+	promQueueCapacity.Set(float64(capacity))
+}
+
+// RecordQueueDropped records a request of n instances of template was
+// dropped from the ingestion queue for reason.
+func RecordQueueDropped(template, reason string, n int) {
+	defaultRegistry.Count(QueueDropped, float64(n), "template", template, "reason", reason)
+	promQueueDropped.WithLabelValues(template, reason).Add(float64(n))
+}
+
+// RecordMetricFolded records a data point of the named metric was folded
+// into its overflow series because the metric reached its cardinality
+// limit.
+func RecordMetricFolded(name string) {
+	defaultRegistry.Count(MetricFolded, 1, "metric", name)
+	promMetricFolded.WithLabelValues(name).Inc()
+}
+
+// RecordSpansDropped records n spans were deliberately not sent to New
+// Relic for reason, e.g. because they were sampled out.
+func RecordSpansDropped(reason string, n int) {
+	defaultRegistry.Count(SpansDropped, float64(n), "reason", reason)
+	promSpansDropped.WithLabelValues(reason).Add(float64(n))
+}
+
+// RecordSpanRateLimited records a span of service was dropped for
+// exceeding the rate limit of the service. The Prometheus counter is
+// labelled with promService instead, which should be one of a bounded set
+// as the counter is never reset.
+func RecordSpanRateLimited(service, promService string) {
+	defaultRegistry.Count(SpansDropped, 1, "reason", "rate_limited", "service", service)
+	promSpansDropped.WithLabelValues("rate_limited").Inc()
+	promSpansRateLimited.WithLabelValues(promService).Inc()
+}
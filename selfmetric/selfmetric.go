@@ -0,0 +1,228 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selfmetric records metrics about the adapter itself. These are
+// reported alongside the Istio data under the reserved
+// `newrelic.istio.adapter.` namespace.
+package selfmetric
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+// Namespace prefixes the names of all self metrics.
+const Namespace = "newrelic.istio.adapter."
+
+// Names of the self metrics.
+const (
+	// InstancesReceived counts the instances received for each template.
+	InstancesReceived = Namespace + "instances.received"
+	// ConversionErrors counts the instances dropped because they could
+	// not be converted, by template and reason.
+	ConversionErrors = Namespace + "conversion.errors"
+	// HandlerBuilds counts the handlers built for handler
+	// configurations, and whether the configuration had been built before.
+	HandlerBuilds = Namespace + "handler.builds"
+	// RPCDuration summarizes the seconds taken to handle Mixer RPCs, by
+	// template and status.
+	RPCDuration = Namespace + "rpc.duration.seconds"
+	// HarvestPayloadBytes summarizes the size of the payloads posted to
+	// each New Relic endpoint.
+	HarvestPayloadBytes = Namespace + "harvest.payload.bytes"
+	// HarvestFailures counts the failed posts to each New Relic endpoint,
+	// by response status or `error` if no response was received.
+	HarvestFailures = Namespace + "harvest.failures"
+	// SpoolEntries is the number of payloads waiting in the spool.
+	SpoolEntries = Namespace + "spool.entries"
+	// SpoolBytes is the size of the payloads waiting in the spool.
+	SpoolBytes = Namespace + "spool.bytes"
+	// SpoolOldestAge is the age in seconds of the oldest payload waiting
+	// in the spool.
+	SpoolOldestAge = Namespace + "spool.oldest.age.seconds"
+	// SpoolReplayed counts the spooled payloads sent to New Relic.
+	SpoolReplayed = Namespace + "spool.replayed"
+	// SpoolDropped counts the spooled payloads dropped without being
+	// sent, by reason.
+	SpoolDropped = Namespace + "spool.dropped"
+	// QueueDepth is the number of requests waiting in the ingestion queue.
+	QueueDepth = Namespace + "queue.depth"
+	// QueueDropped counts the instances dropped from the ingestion queue,
+	// by template and reason.
+	QueueDropped = Namespace + "queue.dropped"
+	// SpansDropped counts the spans deliberately not sent to New Relic,
+	// by reason.
+	SpansDropped = Namespace + "spans.dropped"
+	// MetricFolded counts the data points folded into the overflow series
+	// of a metric that reached its cardinality limit, by metric.
+	MetricFolded = Namespace + "metric.folded"
+)
+
+// VersionAttribute is the attribute holding the adapter version on every
+// self metric.
+const VersionAttribute = "adapter.version"
+
+// seriesKey identifies a metric with a particular attribute set.
+type seriesKey struct {
+	name  string
+	attrs string
+}
+
+// Registry aggregates self metrics until they are recorded with an
+// exporter. The zero value is not usable, use NewRegistry.
+type Registry struct {
+	lock      sync.Mutex
+	version   string
+	start     time.Time
+	counts    map[seriesKey]*telemetry.Count
+	summaries map[seriesKey]*telemetry.Summary
+	gauges    map[seriesKey]*telemetry.Gauge
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		start:     time.Now(),
+		counts:    make(map[seriesKey]*telemetry.Count),
+		summaries: make(map[seriesKey]*telemetry.Summary),
+		gauges:    make(map[seriesKey]*telemetry.Gauge),
+	}
+}
+
+// defaultRegistry is the Registry the package level functions record to.
+var defaultRegistry = NewRegistry()
+
+// Default returns the Registry the package level functions record to.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// SetVersion sets the adapter version self metrics are tagged with.
+func (r *Registry) SetVersion(version string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.version = version
+}
+
+// key returns the seriesKey of name with the attribute pairs kv.
+func key(name string, kv []string) seriesKey {
+	pairs := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		pairs = append(pairs, kv[i]+"="+kv[i+1])
+	}
+	sort.Strings(pairs)
+	return seriesKey{name: name, attrs: strings.Join(pairs, ",")}
+}
+
+// attributes returns the attributes of the attribute pairs kv.
+func attributes(kv []string) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(kv)/2+1)
+	for i := 0; i+1 < len(kv); i += 2 {
+		attrs[kv[i]] = kv[i+1]
+	}
+	return attrs
+}
+
+// Count adds v to the count metric name with the attribute pairs kv.
+func (r *Registry) Count(name string, v float64, kv ...string) {
+	k := key(name, kv)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	c, ok := r.counts[k]
+	if !ok {
+		c = &telemetry.Count{Name: name, Attributes: attributes(kv)}
+		r.counts[k] = c
+	}
+	c.Value += v
+}
+
+// Summary adds v to the summary metric name with the attribute pairs kv.
+func (r *Registry) Summary(name string, v float64, kv ...string) {
+	k := key(name, kv)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	s, ok := r.summaries[k]
+	if !ok {
+		s = &telemetry.Summary{Name: name, Attributes: attributes(kv), Min: v, Max: v}
+		r.summaries[k] = s
+	}
+	s.Count++
+	s.Sum += v
+	if v < s.Min {
+		s.Min = v
+	}
+	if v > s.Max {
+		s.Max = v
+	}
+}
+
+// Gauge sets the gauge metric name with the attribute pairs kv to v.
+// Unlike counts and summaries, gauges keep their value across flushes.
+func (r *Registry) Gauge(name string, v float64, kv ...string) {
+	k := key(name, kv)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	g, ok := r.gauges[k]
+	if !ok {
+		g = &telemetry.Gauge{Name: name, Attributes: attributes(kv)}
+		r.gauges[k] = g
+	}
+	g.Value = v
+}
+
+// Flush records the metrics aggregated since the last flush with e.
+func (r *Registry) Flush(e export.Exporter) {
+	now := time.Now()
+
+	r.lock.Lock()
+	counts, summaries := r.counts, r.summaries
+	r.counts = make(map[seriesKey]*telemetry.Count)
+	r.summaries = make(map[seriesKey]*telemetry.Summary)
+	gauges := make([]telemetry.Gauge, 0, len(r.gauges))
+	for _, g := range r.gauges {
+		gauges = append(gauges, *g)
+	}
+	start, version := r.start, r.version
+	r.start = now
+	r.lock.Unlock()
+
+	interval := now.Sub(start)
+	for _, c := range counts {
+		c.Attributes[VersionAttribute] = version
+		c.Timestamp, c.Interval = start, interval
+		e.RecordMetric(*c)
+	}
+	for _, s := range summaries {
+		s.Attributes[VersionAttribute] = version
+		s.Timestamp, s.Interval = start, interval
+		e.RecordMetric(*s)
+	}
+	for _, g := range gauges {
+		attrs := make(map[string]interface{}, len(g.Attributes)+1)
+		for k, v := range g.Attributes {
+			attrs[k] = v
+		}
+		attrs[VersionAttribute] = version
+		g.Attributes, g.Timestamp = attrs, now
+		e.RecordMetric(g)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfmetric
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+func TestRegistryFlush(t *testing.T) {
+	r := NewRegistry()
+	r.SetVersion("1.2.3")
+	r.Count(InstancesReceived, 2, "template", "metric")
+	r.Count(InstancesReceived, 3, "template", "metric")
+	r.Count(InstancesReceived, 1, "template", "edge")
+	r.Summary(RPCDuration, 0.5, "template", "metric", "status", "ok")
+	r.Summary(RPCDuration, 1.5, "status", "ok", "template", "metric")
+
+	recorder := &export.Recorder{}
+	r.Flush(recorder)
+
+	counts := make(map[string]float64)
+	var summaries []telemetry.Summary
+	for _, m := range recorder.Metrics() {
+		switch v := m.(type) {
+		case telemetry.Count:
+			counts[v.Attributes["template"].(string)] = v.Value
+			if v.Attributes[VersionAttribute] != "1.2.3" {
+				t.Errorf("expected version attribute, got %v", v.Attributes)
+			}
+		case telemetry.Summary:
+			summaries = append(summaries, v)
+		default:
+			t.Errorf("unexpected metric type %T", m)
+		}
+	}
+	if counts["metric"] != 5 || counts["edge"] != 1 || len(counts) != 2 {
+		t.Errorf("expected counts of 5 metric and 1 edge instances, got %v", counts)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	}
+	if s := summaries[0]; s.Count != 2 || s.Sum != 2 || s.Min != 0.5 || s.Max != 1.5 {
+		t.Errorf("expected summary of 0.5 and 1.5, got %+v", s)
+	}
+
+	// Flushing resets the aggregated metrics.
+	recorder = &export.Recorder{}
+	r.Flush(recorder)
+	if n := len(recorder.Metrics()); n != 0 {
+		t.Errorf("expected no metrics after flush, got %d", n)
+	}
+}
+
+func TestRegistryGauge(t *testing.T) {
+	r := NewRegistry()
+	r.SetVersion("1.2.3")
+	r.Gauge(SpoolEntries, 3)
+	r.Gauge(SpoolEntries, 2)
+
+	// Gauges keep their last value across flushes.
+	for i := 0; i < 2; i++ {
+		recorder := &export.Recorder{}
+		r.Flush(recorder)
+		metrics := recorder.Metrics()
+		if len(metrics) != 1 {
+			t.Fatalf("flush %d: expected 1 metric, got %d", i, len(metrics))
+		}
+		g, ok := metrics[0].(telemetry.Gauge)
+		if !ok || g.Name != SpoolEntries || g.Value != 2 || g.Attributes[VersionAttribute] != "1.2.3" {
+			t.Errorf("flush %d: expected %s gauge of 2, got %#v", i, SpoolEntries, metrics[0])
+		}
+	}
+}
+
+// roundTripFunc is an http.RoundTripper calling itself.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransport(t *testing.T) {
+	testCases := []struct {
+		status   int
+		err      error
+		failures map[string]float64
+	}{
+		{202, nil, map[string]float64{}},
+		{503, nil, map[string]float64{"503": 1}},
+		{0, errors.New("connection refused"), map[string]float64{"error": 1}},
+	}
+
+	for _, tc := range testCases {
+		r := NewRegistry()
+		tr := &transport{
+			registry: r,
+			base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				if tc.err != nil {
+					return nil, tc.err
+				}
+				return &http.Response{StatusCode: tc.status, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil
+			}),
+		}
+		req, err := http.NewRequest("POST", "https://metric-api.newrelic.com/metric/v1", bytes.NewReader(make([]byte, 42)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr.RoundTrip(req)
+
+		recorder := &export.Recorder{}
+		r.Flush(recorder)
+		failures := make(map[string]float64)
+		var size float64
+		for _, m := range recorder.Metrics() {
+			switch v := m.(type) {
+			case telemetry.Count:
+				failures[v.Attributes["status"].(string)] = v.Value
+			case telemetry.Summary:
+				size = v.Sum
+				if v.Attributes["endpoint"] != "metric-api.newrelic.com/metric/v1" {
+					t.Errorf("unexpected endpoint attribute: %v", v.Attributes)
+				}
+			}
+		}
+		if size != 42 {
+			t.Errorf("status %d: expected payload size 42, got %v", tc.status, size)
+		}
+		if len(failures) != len(tc.failures) {
+			t.Errorf("status %d: expected failures %v, got %v", tc.status, tc.failures, failures)
+		}
+		for status, n := range tc.failures {
+			if failures[status] != n {
+				t.Errorf("status %d: expected failures %v, got %v", tc.status, tc.failures, failures)
+			}
+		}
+	}
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	RecordInstancesReceived(MetricTemplate, 3)
+	RecordConversionError(TraceSpanTemplate, "missing_span_id")
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		`newrelic_istio_adapter_instances_received_total{template="metric"} 3`,
+		`newrelic_istio_adapter_conversion_errors_total{reason="missing_span_id",template="tracespan"} 1`,
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, body)
+		}
+	}
+}
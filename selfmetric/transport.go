@@ -0,0 +1,59 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfmetric
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// transport is an http.RoundTripper recording the payload size and
+// failures of the posts to New Relic.
+type transport struct {
+	base     http.RoundTripper
+	registry *Registry
+}
+
+// Transport returns an http.RoundTripper sending requests with base and
+// recording their payload size and failures. A nil base refers to
+// http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, registry: defaultRegistry}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Host + req.URL.Path
+	if req.ContentLength > 0 {
+		t.registry.Summary(HarvestPayloadBytes, float64(req.ContentLength), "endpoint", endpoint)
+		promHarvestPayloadBytes.WithLabelValues(endpoint).Observe(float64(req.ContentLength))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.registry.Count(HarvestFailures, 1, "endpoint", endpoint, "status", "error")
+		promHarvestRequests.WithLabelValues(endpoint, "error").Inc()
+		return resp, err
+	}
+	status := strconv.Itoa(resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		t.registry.Count(HarvestFailures, 1, "endpoint", endpoint, "status", status)
+	}
+	promHarvestRequests.WithLabelValues(endpoint, status).Inc()
+	return resp, nil
+}
@@ -14,26 +14,30 @@
 // limitations under the License.
 
 // nolint:lll
-//go:generate ./bin/mixer_codegen.sh -a ./config/config.proto -x "-s=false -n newrelic -t metric -t tracespan"
+//go:generate ./bin/mixer_codegen.sh -a ./config/config.proto -x "-s=false -n newrelic -t metric -t tracespan -t logentry -t edge"
 
 package newrelic
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/newrelic/newrelic-istio-adapter/config"
+	nredge "github.com/newrelic/newrelic-istio-adapter/edge"
 	"github.com/newrelic/newrelic-istio-adapter/log"
+	nrlogentry "github.com/newrelic/newrelic-istio-adapter/logentry"
 	nrmetric "github.com/newrelic/newrelic-istio-adapter/metric"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
 	"github.com/newrelic/newrelic-istio-adapter/trace"
-	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	adptModel "istio.io/api/mixer/adapter/model/v1beta1"
+	"istio.io/istio/mixer/template/edge"
+	"istio.io/istio/mixer/template/logentry"
 	"istio.io/istio/mixer/template/metric"
 	"istio.io/istio/mixer/template/tracespan"
 )
@@ -43,58 +47,117 @@ type Server struct {
 	listener     net.Listener
 	healthServer *health.Server
 	server       *grpc.Server
-	shutdown     chan error
-
-	rawcfg      []byte
-	builderLock sync.RWMutex
-
-	handler *Handler
-
-	harvester *telemetry.Harvester
+	shutdown     chan struct{}
+	serveErr     error
+
+	builderLock sync.Mutex
+	handlers    *handlerCache
+	queue       *ingestQueue
+
+	account       Account
+	secrets       SecretConfig
+	build         ClientBuilder
+	harvestPeriod time.Duration
+
+	// clientsLock protects clients. Clients no Handler uses are closed
+	// in the background, tracked by releasing.
+	clientsLock sync.Mutex
+	clients     map[Account]*clients
+	releasing   sync.WaitGroup
+
+	done chan struct{}
+	wg   sync.WaitGroup
 }
 
+// ReadinessService is the gRPC health service reporting whether the Server
+// is ready, as set with SetServing. The overall health, which liveness
+// probes check, is serving until the Server is closed.
+const ReadinessService = "readiness"
+
 // Compile time assertion Server implement what it is expected to.
 var (
 	_ metric.HandleMetricServiceServer       = &Server{}
 	_ tracespan.HandleTraceSpanServiceServer = &Server{}
+	_ logentry.HandleLogEntryServiceServer   = &Server{}
+	_ edge.HandleEdgeServiceServer           = &Server{}
 )
 
-// NewServer creates a new Mixer metric handling server.
-func NewServer(addr string, h *telemetry.Harvester, grpcOpt ...grpc.ServerOption) (*Server, error) {
+// NewServer creates a new Mixer metric handling server. Data is reported
+// to account unless a handler configures a different one, reading its API
+// key as allowed by secrets, and build returns the clients for each
+// account data is reported to. The clients of an account other than
+// account are closed once no cached Handler reports to it. The
+// harvestPeriod is the rate aggregated metrics are recorded with the
+// clients. The Handlers built for each handler configuration are cached as
+// configured by cache, and requests are handled from a queue as configured
+// by queue.
+func NewServer(addr string, account Account, secrets SecretConfig, build ClientBuilder, harvestPeriod time.Duration, cache HandlerCacheConfig, queue QueueConfig, grpcOpt ...grpc.ServerOption) (*Server, error) {
 	s := &Server{
-		rawcfg:       []byte{0xff, 0xff},
-		healthServer: health.NewServer(),
-		server:       grpc.NewServer(grpcOpt...),
-		harvester:    h,
+		handlers:      newHandlerCache(cache),
+		healthServer:  health.NewServer(),
+		server:        grpc.NewServer(grpcOpt...),
+		account:       account,
+		secrets:       secrets,
+		build:         build,
+		clients:       make(map[Account]*clients),
+		harvestPeriod: harvestPeriod,
 	}
 
 	var err error
 	if s.listener, err = net.Listen("tcp", addr); err != nil {
+		s.handlers.close()
 		return nil, fmt.Errorf("unable to listen on %q: %v", addr, err)
 	}
+	if queue.Capacity > 0 {
+		s.queue = newIngestQueue(queue)
+	}
 	log.Infof("listening on %q", s.listener.Addr().String())
 
 	metric.RegisterHandleMetricServiceServer(s.server, s)
 	tracespan.RegisterHandleTraceSpanServiceServer(s.server, s)
-	if _, err = s.getHandler(nil); err != nil {
+	logentry.RegisterHandleLogEntryServiceServer(s.server, s)
+	edge.RegisterHandleEdgeServiceServer(s.server, s)
+	h, err := s.getHandler(nil)
+	if err != nil {
+		if s.queue != nil {
+			s.queue.close(context.Background())
+		}
+		s.handlers.close()
+		s.listener.Close()
 		return nil, err
 	}
+	h.release()
 
 	s.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.healthServer.SetServingStatus(ReadinessService, healthpb.HealthCheckResponse_SERVING)
 	healthpb.RegisterHealthServer(s.server, s.healthServer)
 
+	if harvestPeriod > 0 {
+		s.start(harvestPeriod)
+	}
+
 	return s, nil
 }
 
-// getHandler returns the handler for rawcfg if it already exists otherwise it builds it.
+// SetServing sets the status of the ReadinessService reported by the gRPC
+// health service, e.g. to report the Server as not ready while data cannot
+// be delivered.
+func (s *Server) SetServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.healthServer.SetServingStatus(ReadinessService, status)
+}
+
+// getHandler returns the handler for rawcfg if it is cached otherwise it
+// builds and caches it. The handler is kept open, even if it is evicted,
+// until the caller releases it.
 func (s *Server) getHandler(rawcfg []byte) (*Handler, error) {
-	s.builderLock.RLock()
-	if bytes.Equal(rawcfg, s.rawcfg) {
-		h := s.handler
-		s.builderLock.RUnlock()
+	key := hashConfig(rawcfg)
+	if h, ok := s.handlers.get(key, rawcfg, time.Now()); ok {
 		return h, nil
 	}
-	s.builderLock.RUnlock()
 
 	// build a handler for the rawcfg and establish session.
 	cfg := &config.Params{}
@@ -102,64 +165,242 @@ func (s *Server) getHandler(rawcfg []byte) (*Handler, error) {
 		return nil, err
 	}
 
+	account, cerr := resolveAccount(cfg, s.account, s.secrets)
+	if cerr != nil {
+		return nil, cerr
+	}
+
 	s.builderLock.Lock()
 	defer s.builderLock.Unlock()
 
 	// check again if someone else beat you to this.
-	if bytes.Equal(rawcfg, s.rawcfg) {
-		return s.handler, nil
+	if h, ok := s.handlers.get(key, rawcfg, time.Now()); ok {
+		return h, nil
 	}
 
-	mh, err := nrmetric.BuildHandler(cfg, s.harvester)
+	c, err := s.acquireClients(account)
 	if err != nil {
 		return nil, err
 	}
 
-	th, err := trace.BuildHandler(cfg, s.harvester)
+	h, err := s.buildHandler(cfg, account, c)
 	if err != nil {
+		s.releaseClients(account)
 		return nil, err
 	}
+	h.onClose = func() { s.releaseClients(account) }
+	h.acquire()
+
+	rebuild := s.handlers.add(key, rawcfg, h, time.Now())
+	selfmetric.RecordHandlerBuild(rebuild)
+	stats := s.handlers.stats()
+	log.Debugf("built handler, cache hits: %d, misses: %d, rebuilds: %d, evictions: %d",
+		stats.Hits, stats.Misses, stats.Rebuilds, stats.Evictions)
+
+	return h, nil
+}
+
+// buildHandler builds the Handler for cfg reporting to account with c.
+func (s *Server) buildHandler(cfg *config.Params, account Account, c *clients) (*Handler, error) {
+	mh, err := nrmetric.BuildHandler(cfg, c.exporter, s.harvestPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	th, err := trace.BuildHandler(cfg, c.exporter, mh)
+	if err != nil {
+		mh.Close()
+		return nil, err
+	}
+
+	lh, err := nrlogentry.BuildHandler(cfg, c.ingestHarvester)
+	if err != nil {
+		th.Close()
+		mh.Close()
+		return nil, err
+	}
+
+	eh, err := nredge.BuildHandler(cfg, c.ingestHarvester, account.AccountID)
+	if err != nil {
+		th.Close()
+		mh.Close()
+		return nil, err
+	}
+
+	return &Handler{m: mh, t: th, l: lh, e: eh}, nil
+}
+
+// HandlerCacheStats returns the counts of the cache of Handlers built for
+// each handler configuration.
+func (s *Server) HandlerCacheStats() HandlerCacheStats {
+	return s.handlers.stats()
+}
+
+// QueueStats returns the counts of the queue requests are handled from.
+// They are all zero if the queue is disabled.
+func (s *Server) QueueStats() QueueStats {
+	if s.queue == nil {
+		return QueueStats{}
+	}
+	return s.queue.stats()
+}
+
+// handle handles the n instances of a request for template with f and
+// then releases h. If the queue is enabled the request is queued to be
+// handled by a worker and f is called with a context independent of the
+// RPC.
+func (s *Server) handle(ctx context.Context, template string, n int, h *Handler, f func(ctx context.Context) error) error {
+	if s.queue != nil {
+		return s.queue.push(job{template: template, instances: n, handle: f, done: h.release})
+	}
+	defer h.release()
+	if err := f(ctx); err != nil {
+		return fmt.Errorf("failed to handle %s: %v", template, err)
+	}
+	return nil
+}
+
+// acquireClients returns the clients for account, building them if they
+// do not exist, for a Handler to use until it calls releaseClients.
+func (s *Server) acquireClients(account Account) (*clients, error) {
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+	c, err := s.getClients(account)
+	if err != nil {
+		return nil, err
+	}
+	c.refs++
+	return c, nil
+}
+
+// releaseClients records a Handler no longer uses the clients for
+// account. Once none does they are closed in the background, unless they
+// are the clients of the default account the self metrics are recorded
+// with.
+func (s *Server) releaseClients(account Account) {
+	s.clientsLock.Lock()
+	c, ok := s.clients[account]
+	if !ok {
+		s.clientsLock.Unlock()
+		return
+	}
+	c.refs--
+	if c.refs > 0 || account == s.account {
+		s.clientsLock.Unlock()
+		return
+	}
+	delete(s.clients, account)
+	s.releasing.Add(1)
+	s.clientsLock.Unlock()
+
+	go func() {
+		defer s.releasing.Done()
+		c.close(context.Background())
+	}()
+}
+
+// getClients returns the clients for account if they already exist
+// otherwise it builds them. The caller must hold the clientsLock.
+func (s *Server) getClients(account Account) (*clients, error) {
+	if c, ok := s.clients[account]; ok {
+		return c, nil
+	}
+
+	e, ih, err := s.build(account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build clients: %v", err)
+	}
+	c := &clients{exporter: e, ingestHarvester: ih}
+	s.clients[account] = c
+	return c, nil
+}
 
-	s.rawcfg = rawcfg
-	s.handler = &Handler{m: mh, t: th}
+// start records the self metrics with the exporter of the default account
+// every period until the Server is closed.
+func (s *Server) start(period time.Duration) {
+	s.done = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.flushSelfMetrics()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
 
-	return s.handler, nil
+// flushSelfMetrics records the self metrics aggregated since the last
+// flush with the exporter of the default account.
+func (s *Server) flushSelfMetrics() {
+	s.clientsLock.Lock()
+	c, err := s.getClients(s.account)
+	s.clientsLock.Unlock()
+	if err != nil {
+		log.Warnf("failed to record self metrics: %v", err)
+		return
+	}
+	selfmetric.Default().Flush(c.exporter)
 }
 
 // Run starts the Server.
 func (s *Server) Run() {
-	s.shutdown = make(chan error, 1)
+	s.shutdown = make(chan struct{})
 	go func() {
-		err := s.server.Serve(s.listener)
+		// Serve reports being stopped if Close is called before it starts.
+		if err := s.server.Serve(s.listener); err != grpc.ErrServerStopped {
+			s.serveErr = err
+		}
 
-		// notify closer we're done
-		s.shutdown <- err
+		// notify waiters we're done
+		close(s.shutdown)
 	}()
 }
 
-// Wait waits for Server to stop.
+// Wait waits for Server to stop serving RPCs. It is safe to call from
+// multiple goroutines.
 func (s *Server) Wait() error {
 	if s.shutdown == nil {
 		return fmt.Errorf("server not running")
 	}
 
-	err := <-s.shutdown
-	s.shutdown = nil
-	return err
+	<-s.shutdown
+	return s.serveErr
 }
 
-// Close gracefully shuts down Server.
-func (s *Server) Close() error {
+// Close gracefully shuts down Server. New RPCs are refused and in-flight
+// RPCs are drained, and queued requests handled, before all recorded data
+// is sent. Once ctx is done, in-flight RPCs are cancelled, queued requests
+// are dropped and sending data is abandoned.
+func (s *Server) Close(ctx context.Context) error {
 	var results error
 	if s.shutdown != nil {
 		s.healthServer.Shutdown()
-		s.server.GracefulStop()
+		s.drain(ctx)
 		if err := s.Wait(); err != nil {
 			results = err
 		}
 	}
 
-	if s.listener != nil {
+	if s.queue != nil {
+		s.queue.close(ctx)
+	}
+	if s.done != nil {
+		close(s.done)
+		s.wg.Wait()
+		s.done = nil
+	}
+	s.handlers.close()
+	s.flushSelfMetrics()
+	s.flush(ctx)
+
+	// The gRPC server closes the listener once it has served.
+	if s.listener != nil && s.shutdown == nil {
 		if err := s.listener.Close(); err != nil {
 			if results != nil {
 				results = fmt.Errorf("%v: %w", err, results)
@@ -172,29 +413,139 @@ func (s *Server) Close() error {
 	return results
 }
 
+// drain stops the gRPC server once all in-flight RPCs have completed, or
+// cancels them once ctx is done.
+func (s *Server) drain(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Warnf("shutdown deadline exceeded, cancelling in-flight requests")
+		s.server.Stop()
+		<-stopped
+	}
+}
+
+// flush sends the data recorded with the clients of every account and
+// closes them. It blocks until all data has been sent, including by the
+// clients closed in the background, or ctx is done.
+func (s *Server) flush(ctx context.Context) {
+	s.clientsLock.Lock()
+	all := make([]*clients, 0, len(s.clients))
+	for _, c := range s.clients {
+		all = append(all, c)
+	}
+	s.clientsLock.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range all {
+		wg.Add(1)
+		go func(c *clients) {
+			defer wg.Done()
+			c.close(ctx)
+		}(c)
+	}
+	wg.Wait()
+
+	released := make(chan struct{})
+	go func() {
+		s.releasing.Wait()
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-ctx.Done():
+	}
+}
+
 // HandleTraceSpan implements tracespan.HandleMetricServiceServer.
-func (s *Server) HandleTraceSpan(ctx context.Context, r *tracespan.HandleTraceSpanRequest) (*adptModel.ReportResult, error) {
+func (s *Server) HandleTraceSpan(ctx context.Context, r *tracespan.HandleTraceSpanRequest) (_ *adptModel.ReportResult, err error) {
+	selfmetric.RecordInstancesReceived(selfmetric.TraceSpanTemplate, len(r.Instances))
+	defer func(start time.Time) {
+		selfmetric.RecordRPC(selfmetric.TraceSpanTemplate, time.Since(start), err)
+	}(time.Now())
+
 	h, err := s.getHandler(r.AdapterConfig.Value)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = h.HandleTraceSpan(ctx, r.Instances); err != nil {
-		return nil, fmt.Errorf("failed to handle tracespan: %v", err)
+	err = s.handle(ctx, selfmetric.TraceSpanTemplate, len(r.Instances), h, func(ctx context.Context) error {
+		return h.HandleTraceSpan(ctx, r.Instances)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &adptModel.ReportResult{}, nil
 }
 
 // HandleMetric implements metric.HandleMetricServiceServer.
-func (s *Server) HandleMetric(ctx context.Context, r *metric.HandleMetricRequest) (*adptModel.ReportResult, error) {
+func (s *Server) HandleMetric(ctx context.Context, r *metric.HandleMetricRequest) (_ *adptModel.ReportResult, err error) {
+	selfmetric.RecordInstancesReceived(selfmetric.MetricTemplate, len(r.Instances))
+	defer func(start time.Time) {
+		selfmetric.RecordRPC(selfmetric.MetricTemplate, time.Since(start), err)
+	}(time.Now())
+
+	h, err := s.getHandler(r.AdapterConfig.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.handle(ctx, selfmetric.MetricTemplate, len(r.Instances), h, func(ctx context.Context) error {
+		return h.HandleMetric(ctx, r.Instances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &adptModel.ReportResult{}, nil
+}
+
+// HandleLogEntry implements logentry.HandleLogEntryServiceServer.
+func (s *Server) HandleLogEntry(ctx context.Context, r *logentry.HandleLogEntryRequest) (_ *adptModel.ReportResult, err error) {
+	selfmetric.RecordInstancesReceived(selfmetric.LogEntryTemplate, len(r.Instances))
+	defer func(start time.Time) {
+		selfmetric.RecordRPC(selfmetric.LogEntryTemplate, time.Since(start), err)
+	}(time.Now())
+
 	h, err := s.getHandler(r.AdapterConfig.Value)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = h.HandleMetric(ctx, r.Instances); err != nil {
-		return nil, fmt.Errorf("failed to handle metric: %v", err)
+	err = s.handle(ctx, selfmetric.LogEntryTemplate, len(r.Instances), h, func(ctx context.Context) error {
+		return h.HandleLogEntry(ctx, r.Instances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &adptModel.ReportResult{}, nil
+}
+
+// HandleEdge implements edge.HandleEdgeServiceServer.
+func (s *Server) HandleEdge(ctx context.Context, r *edge.HandleEdgeRequest) (_ *adptModel.ReportResult, err error) {
+	selfmetric.RecordInstancesReceived(selfmetric.EdgeTemplate, len(r.Instances))
+	defer func(start time.Time) {
+		selfmetric.RecordRPC(selfmetric.EdgeTemplate, time.Since(start), err)
+	}(time.Now())
+
+	h, err := s.getHandler(r.AdapterConfig.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.handle(ctx, selfmetric.EdgeTemplate, len(r.Instances), h, func(ctx context.Context) error {
+		return h.HandleEdge(ctx, r.Instances)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &adptModel.ReportResult{}, nil
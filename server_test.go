@@ -0,0 +1,134 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelic
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-istio-adapter/ingest"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/metric"
+)
+
+func TestCloseFlushesRecordedData(t *testing.T) {
+	recorder := &export.Recorder{}
+	build := func(Account) (export.Exporter, *ingest.Harvester, error) {
+		return recorder, nil, nil
+	}
+
+	// A long harvest period so data is only recorded on Close.
+	s, err := NewServer(":0", Account{}, SecretConfig{}, build, time.Hour, HandlerCacheConfig{}, QueueConfig{})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	s.Run()
+
+	rawcfg, err := (&config.Params{
+		Metrics: map[string]*config.Params_MetricInfo{
+			"requests.instance.istio-system": {Name: "requests", Type: config.COUNT},
+		},
+	}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := s.getHandler(rawcfg)
+	if err != nil {
+		t.Fatalf("failed to get handler: %v", err)
+	}
+	err = h.HandleMetric(context.Background(), []*metric.InstanceMsg{{
+		Name:       "requests.instance.istio-system",
+		Value:      &policy.Value{Value: &policy.Value_Int64Value{Int64Value: 1}},
+		Dimensions: map[string]*policy.Value{},
+	}})
+	if err != nil {
+		t.Fatalf("HandleMetric errored: %v", err)
+	}
+	h.release()
+	if n := len(recorder.Metrics()); n != 0 {
+		t.Fatalf("expected no metrics recorded before Close, got %d", n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Close(ctx); err != nil {
+		t.Errorf("Close errored: %v", err)
+	}
+
+	var names []string
+	selfMetrics := make(map[string]bool)
+	for _, m := range recorder.Metrics() {
+		switch v := m.(type) {
+		case telemetry.Count:
+			if strings.HasPrefix(v.Name, selfmetric.Namespace) {
+				selfMetrics[v.Name] = true
+			} else {
+				names = append(names, v.Name)
+			}
+		case telemetry.Gauge:
+			if !strings.HasPrefix(v.Name, selfmetric.Namespace) {
+				t.Errorf("unexpected metric %#v", m)
+			}
+		default:
+			t.Errorf("unexpected metric %#v", m)
+		}
+	}
+	if len(names) != 1 || names[0] != "requests" {
+		t.Errorf("expected requests metric recorded on Close, got %v", names)
+	}
+	if !selfMetrics[selfmetric.HandlerBuilds] {
+		t.Errorf("expected %s self metric recorded on Close, got %v", selfmetric.HandlerBuilds, selfMetrics)
+	}
+	if n := recorder.Flushes(); n != 1 {
+		t.Errorf("expected 1 flush on Close, got %d", n)
+	}
+	if err := s.Wait(); err != nil {
+		t.Errorf("expected server to have stopped, got %v", err)
+	}
+}
+
+func TestSetServing(t *testing.T) {
+	s := newTestServer(t, HandlerCacheConfig{})
+	defer s.Close(context.Background())
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := s.healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("health check errored: %v", err)
+		}
+		return resp.Status
+	}
+	for _, serving := range []bool{false, true} {
+		s.SetServing(serving)
+		expected := healthpb.HealthCheckResponse_NOT_SERVING
+		if serving {
+			expected = healthpb.HealthCheckResponse_SERVING
+		}
+		if status := check(ReadinessService); status != expected {
+			t.Errorf("expected readiness status %v, got %v", expected, status)
+		}
+		// Readiness does not affect liveness.
+		if status := check(""); status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("expected overall status SERVING, got %v", status)
+		}
+	}
+}
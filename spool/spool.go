@@ -0,0 +1,557 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spool persists the payloads that fail to post to New Relic to
+// disk and replays them in order once their endpoint is reachable again.
+package spool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/log"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+)
+
+// Config configures a Spool.
+type Config struct {
+	// Dir is the directory payloads are spooled to. It is created if it
+	// does not exist.
+	Dir string
+	// MaxSize is the maximum size in bytes of the spooled payloads. The
+	// oldest payloads are dropped to make room for new ones. Zero or less
+	// means the size is not limited.
+	MaxSize int64
+	// MaxAge is how long a payload is kept in the spool. Zero or less
+	// means payloads are kept until they are sent.
+	MaxAge time.Duration
+	// RetryPeriod is how often sending the spooled payloads is retried.
+	// Zero or less means they are only sent by calling Replay.
+	RetryPeriod time.Duration
+}
+
+// Reasons spooled payloads are dropped for.
+const (
+	dropAge        = "age"
+	dropSize       = "size"
+	dropRejected   = "rejected"
+	dropUnreadable = "unreadable"
+)
+
+const (
+	// fileExt is the extension of spool files.
+	fileExt = ".payload"
+	// tmpExt is the extension of spool files being written.
+	tmpExt = ".tmp"
+	// sendTimeout bounds how long sending a spooled payload may take.
+	sendTimeout = 30 * time.Second
+)
+
+// header is the first line of a spool file, followed by the request body.
+type header struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+}
+
+// destination identifies where a payload is posted, a hash of its URL and
+// API key. The payloads of each destination are sent in order,
+// independently of the other destinations.
+type destination uint64
+
+// destinationOf returns the destination of req.
+func destinationOf(req *http.Request) destination {
+	h := fnv.New64a()
+	h.Write([]byte(req.URL.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("Api-Key")))
+	return destination(h.Sum64())
+}
+
+// entry is a payload in the spool.
+type entry struct {
+	// seq is the unix time in nanoseconds the payload was spooled. It
+	// also orders the payloads and, with dest, names the spool file.
+	seq  int64
+	dest destination
+	size int64
+}
+
+func (e entry) name() string {
+	return fmt.Sprintf("%020d-%016x%s", e.seq, uint64(e.dest), fileExt)
+}
+
+// parseEntry returns the entry of the spool file name, or false if name
+// is not one.
+func parseEntry(name string) (entry, bool) {
+	fields := strings.Split(strings.TrimSuffix(name, fileExt), "-")
+	if len(fields) != 2 {
+		return entry{}, false
+	}
+	seq, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return entry{}, false
+	}
+	dest, err := strconv.ParseUint(fields[1], 16, 64)
+	if err != nil {
+		return entry{}, false
+	}
+	return entry{seq: seq, dest: destination(dest)}, true
+}
+
+func (e entry) spooled() time.Time {
+	return time.Unix(0, e.seq)
+}
+
+// Spool is an http.RoundTripper spooling the requests that fail with a
+// network error or a response status worth retrying, and every request
+// made while payloads for the same URL and API key are waiting in the
+// spool so they are sent in order. Spooled requests are answered with a
+// 202 Accepted response.
+//
+// Spool files include the request headers, the API key among them, and
+// are only readable by the user running the adapter.
+type Spool struct {
+	cfg  Config
+	base http.RoundTripper
+	now  func() time.Time
+
+	// lock protects the fields below. Each queue holds the payloads of a
+	// destination, oldest first.
+	lock    sync.Mutex
+	queues  map[destination][]entry
+	entries int
+	size    int64
+	lastSeq int64
+
+	// replayLock serializes replays so payloads are sent in order.
+	replayLock sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a Spool sending requests with base, replaying the payloads
+// left in the spool directory by a previous run. A nil base refers to
+// http.DefaultTransport.
+func New(cfg Config, base http.RoundTripper) (*Spool, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %v", err)
+	}
+
+	s := &Spool{cfg: cfg, base: base, now: time.Now, queues: make(map[destination][]entry)}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if s.entries > 0 {
+		log.Infof("found %d spooled payloads in %q", s.entries, cfg.Dir)
+	}
+
+	s.lock.Lock()
+	s.trim(s.now())
+	s.lock.Unlock()
+
+	if cfg.RetryPeriod > 0 {
+		s.start(cfg.RetryPeriod)
+	}
+	return s, nil
+}
+
+// load reads the entries of the spool files in the spool directory and
+// removes the files left partially written.
+func (s *Spool) load() error {
+	files, err := ioutil.ReadDir(s.cfg.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory: %v", err)
+	}
+	for _, f := range files {
+		name := f.Name()
+		switch filepath.Ext(name) {
+		case tmpExt:
+			os.Remove(filepath.Join(s.cfg.Dir, name))
+		case fileExt:
+			e, ok := parseEntry(name)
+			if !ok || f.IsDir() {
+				continue
+			}
+			e.size = f.Size()
+			s.queues[e.dest] = append(s.queues[e.dest], e)
+			s.entries++
+			s.size += e.size
+			if e.seq > s.lastSeq {
+				s.lastSeq = e.seq
+			}
+		}
+	}
+	for _, q := range s.queues {
+		sort.Slice(q, func(i, j int) bool { return q[i].seq < q[j].seq })
+	}
+	return nil
+}
+
+// Depth returns the number of payloads in the spool and their size.
+func (s *Spool) Depth() (entries int, size int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.entries, s.size
+}
+
+// queued returns whether payloads for dest are waiting in the spool.
+func (s *Spool) queued(dest destination) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.queues[dest]) > 0
+}
+
+// retryable returns whether a request answered with resp and err is worth
+// sending again.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+}
+
+// discard reads and closes the body of resp.
+func discard(resp *http.Response) {
+	if resp != nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// withBody returns a copy of req sending body.
+func withBody(req *http.Request, body []byte) *http.Request {
+	r := req.Clone(req.Context())
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return r
+}
+
+// accepted returns the response to a spooled request.
+func accepted(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "202 Accepted",
+		StatusCode: http.StatusAccepted,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (s *Spool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !s.queued(destinationOf(req)) {
+		resp, err := s.base.RoundTrip(withBody(req, body))
+		if !retryable(resp, err) {
+			return resp, err
+		}
+		discard(resp)
+		log.Debugf("spooling payload for %s after failed post", req.URL.Host+req.URL.Path)
+	}
+
+	if err := s.add(req, body); err != nil {
+		return nil, fmt.Errorf("failed to spool payload: %v", err)
+	}
+	return accepted(req), nil
+}
+
+// add writes the request req with body to the spool.
+func (s *Spool) add(req *http.Request, body []byte) error {
+	hdr, err := json.Marshal(header{Method: req.Method, URL: req.URL.String(), Header: req.Header})
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	e := entry{seq: now.UnixNano(), dest: destinationOf(req), size: int64(len(hdr) + 1 + len(body))}
+	if e.seq <= s.lastSeq {
+		e.seq = s.lastSeq + 1
+	}
+	s.lastSeq = e.seq
+
+	// Write to a temporary file first so a partially written payload is
+	// never replayed.
+	path := filepath.Join(s.cfg.Dir, e.name())
+	f, err := os.OpenFile(path+tmpExt, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	w.Write(hdr)
+	w.WriteByte('\n')
+	w.Write(body)
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(path + tmpExt)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path + tmpExt)
+		return err
+	}
+	if err := os.Rename(path+tmpExt, path); err != nil {
+		os.Remove(path + tmpExt)
+		return err
+	}
+
+	s.queues[e.dest] = append(s.queues[e.dest], e)
+	s.entries++
+	s.size += e.size
+	s.trim(now)
+	return nil
+}
+
+// trim drops the oldest payloads that are older than the maximum age or
+// do not fit the maximum size, and records the spool depth. The caller
+// must hold the lock.
+func (s *Spool) trim(now time.Time) {
+	dropped := make(map[string]int)
+	if s.cfg.MaxAge > 0 {
+		for dest, q := range s.queues {
+			for len(q) > 0 && now.Sub(q[0].spooled()) > s.cfg.MaxAge {
+				dropped[dropAge]++
+				s.removeHead(dest)
+				q = s.queues[dest]
+			}
+		}
+	}
+	for s.cfg.MaxSize > 0 && s.size > s.cfg.MaxSize {
+		dest, ok := s.oldest()
+		if !ok {
+			break
+		}
+		dropped[dropSize]++
+		s.removeHead(dest)
+	}
+	for reason, n := range dropped {
+		log.Warnf("dropped %d spooled payloads: %s limit exceeded", n, reason)
+		selfmetric.RecordSpoolDropped(reason, n)
+	}
+	s.recordDepth(now)
+}
+
+// oldest returns the destination of the oldest payload, or false if the
+// spool is empty. The caller must hold the lock.
+func (s *Spool) oldest() (destination, bool) {
+	var oldest destination
+	seq := int64(-1)
+	for dest, q := range s.queues {
+		if seq < 0 || q[0].seq < seq {
+			oldest, seq = dest, q[0].seq
+		}
+	}
+	return oldest, seq >= 0
+}
+
+// removeHead removes the oldest payload of dest. The caller must hold the
+// lock.
+func (s *Spool) removeHead(dest destination) {
+	q := s.queues[dest]
+	e := q[0]
+	if err := os.Remove(filepath.Join(s.cfg.Dir, e.name())); err != nil && !os.IsNotExist(err) {
+		log.Errorf("failed to remove spooled payload: %v", err)
+	}
+	if len(q) == 1 {
+		delete(s.queues, dest)
+	} else {
+		s.queues[dest] = q[1:]
+	}
+	s.entries--
+	s.size -= e.size
+}
+
+// recordDepth records the self metrics of the spool depth. The caller
+// must hold the lock.
+func (s *Spool) recordDepth(now time.Time) {
+	var age time.Duration
+	if dest, ok := s.oldest(); ok {
+		age = now.Sub(s.queues[dest][0].spooled())
+	}
+	selfmetric.RecordSpoolDepth(s.entries, s.size, age)
+}
+
+// read returns the request spooled as e.
+func (s *Spool) read(e entry) (*http.Request, error) {
+	b, err := ioutil.ReadFile(filepath.Join(s.cfg.Dir, e.name()))
+	if err != nil {
+		return nil, err
+	}
+	i := bytes.IndexByte(b, '\n')
+	if i < 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+	var hdr header
+	if err := json.Unmarshal(b[:i], &hdr); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	body := b[i+1:]
+
+	req, err := http.NewRequest(hdr.Method, hdr.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = hdr.Header
+	return req, nil
+}
+
+// done removes e from the spool if it is still the oldest payload of its
+// destination.
+func (s *Spool) done(e entry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if q := s.queues[e.dest]; len(q) > 0 && q[0] == e {
+		s.removeHead(e.dest)
+	}
+	s.recordDepth(s.now())
+}
+
+// Replay sends the spooled payloads of each destination in order until
+// one fails to send or none are left. Destinations are replayed
+// concurrently, so one that is unreachable does not hold back the others.
+// Payloads rejected by New Relic are dropped.
+func (s *Spool) Replay() {
+	s.replayLock.Lock()
+	defer s.replayLock.Unlock()
+
+	s.lock.Lock()
+	s.trim(s.now())
+	dests := make([]destination, 0, len(s.queues))
+	for dest := range s.queues {
+		dests = append(dests, dest)
+	}
+	s.lock.Unlock()
+
+	var wg sync.WaitGroup
+	for _, dest := range dests {
+		wg.Add(1)
+		go func(dest destination) {
+			defer wg.Done()
+			s.replay(dest)
+		}(dest)
+	}
+	wg.Wait()
+}
+
+// replay sends the spooled payloads of dest in order until one fails to
+// send or none are left.
+func (s *Spool) replay(dest destination) {
+	for s.ctx.Err() == nil {
+		s.lock.Lock()
+		s.trim(s.now())
+		q := s.queues[dest]
+		if len(q) == 0 {
+			s.lock.Unlock()
+			return
+		}
+		e := q[0]
+		s.lock.Unlock()
+
+		req, err := s.read(e)
+		if err != nil {
+			log.Errorf("failed to read spooled payload %s: %v", e.name(), err)
+			selfmetric.RecordSpoolDropped(dropUnreadable, 1)
+			s.done(e)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, sendTimeout)
+		resp, err := s.base.RoundTrip(req.WithContext(ctx))
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		retry := retryable(resp, err)
+		discard(resp)
+		cancel()
+
+		switch {
+		case retry:
+			log.Debugf("failed to send spooled payload, retrying later")
+			return
+		case status < 200 || status >= 300:
+			log.Errorf("dropped spooled payload for %s: unexpected response status %d", req.URL.Host+req.URL.Path, status)
+			selfmetric.RecordSpoolDropped(dropRejected, 1)
+		default:
+			selfmetric.RecordSpoolReplayed()
+		}
+		s.done(e)
+	}
+}
+
+// start replays the spooled payloads every period until the Spool is
+// closed.
+func (s *Spool) start(period time.Duration) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Replay()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Close stops replaying the spooled payloads. Payloads left in the spool
+// are replayed by the next Spool using the same directory.
+func (s *Spool) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
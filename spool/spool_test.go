@@ -0,0 +1,276 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// endpoint is an http.RoundTripper standing in for New Relic. It records
+// the bodies of the requests it accepts. Requests made with the API key
+// unavailable are answered with a 503.
+type endpoint struct {
+	lock        sync.Mutex
+	status      int
+	err         error
+	unavailable string
+	posts       int
+	bodies      []string
+}
+
+func (e *endpoint) set(status int, err error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.status, e.err = status, err
+}
+
+func (e *endpoint) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.posts++
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.unavailable != "" && req.Header.Get("Api-Key") == e.unavailable {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+	}
+	if e.status == http.StatusAccepted {
+		e.bodies = append(e.bodies, req.Header.Get("Api-Key")+":"+string(body))
+	}
+	return &http.Response{StatusCode: e.status, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+}
+
+func newTestSpool(t *testing.T, cfg Config, base http.RoundTripper) *Spool {
+	s, err := New(cfg, base)
+	if err != nil {
+		t.Fatalf("failed to create spool: %v", err)
+	}
+	return s
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func post(t *testing.T, rt http.RoundTripper, body string) {
+	postKey(t, rt, "key", body)
+}
+
+func postKey(t *testing.T, rt http.RoundTripper, key, body string) {
+	req, err := http.NewRequest("POST", "https://metric-api.newrelic.com/metric/v1", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Api-Key", key)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("post %q errored: %v", body, err)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("post %q: expected status 202, got %d", body, resp.StatusCode)
+	}
+}
+
+func TestSpoolReplaysInOrder(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	e := &endpoint{status: http.StatusServiceUnavailable}
+	s := newTestSpool(t, Config{Dir: dir}, e)
+	defer s.Close()
+
+	post(t, s, "a")
+	e.set(0, errors.New("connection refused"))
+	post(t, s, "b")
+	e.set(http.StatusAccepted, nil)
+	// Sent to the spool behind a and b rather than to New Relic.
+	post(t, s, "c")
+
+	if n, _ := s.Depth(); n != 3 {
+		t.Fatalf("expected 3 spooled payloads, got %d", n)
+	}
+	if e.posts != 1 {
+		t.Errorf("expected only the first payload posted while spooling, got %d posts", e.posts)
+	}
+
+	s.Replay()
+	expected := []string{"key:a", "key:b", "key:c"}
+	if strings.Join(e.bodies, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected payloads %v replayed, got %v", expected, e.bodies)
+	}
+	if n, size := s.Depth(); n != 0 || size != 0 {
+		t.Errorf("expected empty spool after replay, got %d payloads of %d bytes", n, size)
+	}
+
+	// Posts go straight to New Relic once the spool is empty.
+	post(t, s, "d")
+	if len(e.bodies) != 4 {
+		t.Errorf("expected payload posted, got %v", e.bodies)
+	}
+}
+
+func TestSpoolStopsReplayOnFailure(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	e := &endpoint{status: http.StatusTooManyRequests}
+	s := newTestSpool(t, Config{Dir: dir}, e)
+	defer s.Close()
+
+	post(t, s, "a")
+	post(t, s, "b")
+	s.Replay()
+
+	if e.posts != 2 {
+		t.Errorf("expected replay to stop after the first failed post, got %d posts", e.posts)
+	}
+	if n, _ := s.Depth(); n != 2 {
+		t.Errorf("expected 2 spooled payloads, got %d", n)
+	}
+}
+
+func TestSpoolQueuesPerDestination(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	e := &endpoint{status: http.StatusServiceUnavailable}
+	s := newTestSpool(t, Config{Dir: dir}, e)
+	defer s.Close()
+
+	postKey(t, s, "a", "1")
+	postKey(t, s, "b", "1")
+	e.set(http.StatusAccepted, nil)
+	e.unavailable = "a"
+	postKey(t, s, "a", "2")
+	s.Replay()
+
+	// The payloads of b are sent while a is still unavailable.
+	if strings.Join(e.bodies, ",") != "b:1" {
+		t.Errorf("expected payload b:1 replayed, got %v", e.bodies)
+	}
+	if n, _ := s.Depth(); n != 2 {
+		t.Errorf("expected 2 spooled payloads for a, got %d", n)
+	}
+	// Posts for b go straight to New Relic while a is spooling.
+	postKey(t, s, "b", "2")
+	if strings.Join(e.bodies, ",") != "b:1,b:2" {
+		t.Errorf("expected payload b:2 posted, got %v", e.bodies)
+	}
+
+	e.unavailable = ""
+	s.Replay()
+	if strings.Join(e.bodies, ",") != "b:1,b:2,a:1,a:2" {
+		t.Errorf("expected payloads of a replayed in order, got %v", e.bodies)
+	}
+}
+
+func TestSpoolDropsRejectedPayloads(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	e := &endpoint{status: http.StatusBadGateway}
+	s := newTestSpool(t, Config{Dir: dir}, e)
+	defer s.Close()
+
+	post(t, s, "a")
+	e.set(http.StatusBadRequest, nil)
+	s.Replay()
+
+	if n, _ := s.Depth(); n != 0 {
+		t.Errorf("expected rejected payload dropped, got %d spooled payloads", n)
+	}
+}
+
+func TestSpoolPersists(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	e := &endpoint{status: http.StatusServiceUnavailable}
+	s := newTestSpool(t, Config{Dir: dir}, e)
+	post(t, s, "a")
+	post(t, s, "b")
+	s.Close()
+
+	// A partially written payload is discarded.
+	if err := ioutil.WriteFile(dir+"/00000000000000000001"+fileExt+tmpExt, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	e.set(http.StatusAccepted, nil)
+	s = newTestSpool(t, Config{Dir: dir}, e)
+	defer s.Close()
+	if n, _ := s.Depth(); n != 2 {
+		t.Fatalf("expected 2 spooled payloads loaded, got %d", n)
+	}
+	s.Replay()
+	if strings.Join(e.bodies, ",") != "key:a,key:b" {
+		t.Errorf("expected payloads a and b replayed, got %v", e.bodies)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("expected no spool files left, got %d", len(files))
+	}
+}
+
+func TestSpoolLimits(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	e := &endpoint{status: http.StatusServiceUnavailable}
+	s := newTestSpool(t, Config{Dir: dir, MaxAge: time.Minute}, e)
+	defer s.Close()
+
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	post(t, s, "a")
+	now = now.Add(45 * time.Second)
+	post(t, s, "b")
+	now = now.Add(30 * time.Second)
+	post(t, s, "c")
+	if n, _ := s.Depth(); n != 2 {
+		t.Errorf("expected payload older than the maximum age dropped, got %d spooled payloads", n)
+	}
+
+	_, size := s.Depth()
+	s.cfg.MaxSize = size
+	post(t, s, "d")
+	if n, sz := s.Depth(); n != 2 || sz > size {
+		t.Errorf("expected oldest payload dropped to fit %d bytes, got %d payloads of %d bytes", size, n, sz)
+	}
+
+	e.set(http.StatusAccepted, nil)
+	s.Replay()
+	if strings.Join(e.bodies, ",") != "key:c,key:d" {
+		t.Errorf("expected payloads c and d replayed, got %v", e.bodies)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/convert"
+	"istio.io/istio/mixer/pkg/adapter"
+)
+
+// conventionNames maps the names of span attributes to the names New Relic
+// APM agents send them as.
+var conventionNames = map[string]string{
+	"response.code":  "http.statusCode",
+	"request.method": "http.method",
+}
+
+// attributeMapper renames and filters the attributes of spans.
+type attributeMapper struct {
+	conventions bool
+	rename      map[string]string
+	filter      convert.AttributeFilter
+}
+
+// newAttributeMapper returns the attributeMapper configured by t, or nil
+// if the attributes of spans are sent as converted.
+func newAttributeMapper(t *config.Params_Traces) *attributeMapper {
+	if !t.GetNewRelicConventions() && len(t.GetRenameAttributes()) == 0 &&
+		len(t.GetIncludeAttributes()) == 0 && len(t.GetExcludeAttributes()) == 0 {
+		return nil
+	}
+	return &attributeMapper{
+		conventions: t.GetNewRelicConventions(),
+		rename:      t.GetRenameAttributes(),
+		filter: convert.AttributeFilter{
+			Include: t.GetIncludeAttributes(),
+			Exclude: t.GetExcludeAttributes(),
+		},
+	}
+}
+
+// apply renames and filters attrs, the attributes of a span converted
+// from a tracespan instance.
+func (m *attributeMapper) apply(attrs map[string]interface{}, clientSpan bool) map[string]interface{} {
+	if m == nil {
+		return attrs
+	}
+
+	if m.conventions {
+		applyConventions(attrs, clientSpan)
+	}
+	// Attributes are renamed all at once so renaming one attribute to the
+	// name of another that is itself renamed is not order dependent.
+	renamed := make(map[string]interface{}, len(m.rename))
+	for from, to := range m.rename {
+		v, ok := attrs[from]
+		if !ok {
+			continue
+		}
+		delete(attrs, from)
+		if to != "" {
+			renamed[to] = v
+		}
+	}
+	for name, v := range renamed {
+		attrs[name] = v
+	}
+	return m.filter.Apply(attrs)
+}
+
+// applyConventions renames attrs and adds the attributes New Relic APM
+// agents send with their spans.
+func applyConventions(attrs map[string]interface{}, clientSpan bool) {
+	for from, to := range conventionNames {
+		if v, ok := attrs[from]; ok {
+			delete(attrs, from)
+			attrs[to] = v
+		}
+	}
+
+	if clientSpan {
+		peer := attrs["destination.ip"]
+		if port, ok := attrs["destination.port"]; ok {
+			peer = fmt.Sprintf("%v:%v", peer, port)
+		}
+		attrs["peer.address"] = peer
+	} else {
+		attrs["peer.address"] = attrs["source.ip"]
+	}
+}
+
+// validateTraces returns any errors found validating the span attribute
+// configuration.
+func validateTraces(t *config.Params_Traces) (errs *adapter.ConfigErrors) {
+	for _, p := range t.GetIncludeAttributes() {
+		if err := convert.ValidatePattern(p); err != nil {
+			errs = errs.Appendf("Traces.include_attributes", "pattern %q is invalid: %v", p, err)
+		}
+	}
+	for _, p := range t.GetExcludeAttributes() {
+		if err := convert.ValidatePattern(p); err != nil {
+			errs = errs.Appendf("Traces.exclude_attributes", "pattern %q is invalid: %v", p, err)
+		}
+	}
+	for from := range t.GetRenameAttributes() {
+		if from == "" {
+			errs = errs.Appendf("Traces.rename_attributes", "attribute name must be non-empty")
+		}
+	}
+	return errs
+}
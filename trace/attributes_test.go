@@ -0,0 +1,131 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+)
+
+func TestAttributeMapper(t *testing.T) {
+	attrs := func() map[string]interface{} {
+		return map[string]interface{}{
+			"response.code":    int64(503),
+			"request.method":   "GET",
+			"source.ip":        "10.0.0.1",
+			"destination.ip":   "10.0.0.2",
+			"destination.port": int64(9080),
+			"api.protocol":     "http",
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		traces     *config.Params_Traces
+		clientSpan bool
+		expected   map[string]interface{}
+	}{
+		{
+			name:     "none",
+			expected: attrs(),
+		},
+		{
+			name:   "server span conventions",
+			traces: &config.Params_Traces{NewRelicConventions: true},
+			expected: map[string]interface{}{
+				"http.statusCode":  int64(503),
+				"http.method":      "GET",
+				"source.ip":        "10.0.0.1",
+				"destination.ip":   "10.0.0.2",
+				"destination.port": int64(9080),
+				"api.protocol":     "http",
+				"peer.address":     "10.0.0.1",
+			},
+		},
+		{
+			name:       "client span conventions",
+			traces:     &config.Params_Traces{NewRelicConventions: true, IncludeAttributes: []string{"peer.*"}},
+			clientSpan: true,
+			expected: map[string]interface{}{
+				"peer.address": "10.0.0.2:9080",
+			},
+		},
+		{
+			name: "rename",
+			traces: &config.Params_Traces{RenameAttributes: map[string]string{
+				"source.ip":      "destination.ip",
+				"destination.ip": "peer.ip",
+				"api.protocol":   "",
+			}},
+			expected: map[string]interface{}{
+				"response.code":    int64(503),
+				"request.method":   "GET",
+				"destination.ip":   "10.0.0.1",
+				"peer.ip":          "10.0.0.2",
+				"destination.port": int64(9080),
+			},
+		},
+		{
+			name: "filter renamed",
+			traces: &config.Params_Traces{
+				RenameAttributes:  map[string]string{"response.code": "status"},
+				IncludeAttributes: []string{"status", "destination.*"},
+				ExcludeAttributes: []string{"*.port"},
+			},
+			expected: map[string]interface{}{
+				"status":         int64(503),
+				"destination.ip": "10.0.0.2",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		m := newAttributeMapper(tc.traces)
+		if got := m.apply(attrs(), tc.clientSpan); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %#v, got %#v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestBuildHandlerTraces(t *testing.T) {
+	testCases := []struct {
+		name           string
+		traces         *config.Params_Traces
+		expectedErrMsg string
+	}{
+		{"none", nil, ""},
+		{"valid", &config.Params_Traces{IncludeAttributes: []string{"source.*"}, RenameAttributes: map[string]string{"a": "b"}}, ""},
+		{"invalid include", &config.Params_Traces{IncludeAttributes: []string{"["}}, "include_attributes"},
+		{"invalid exclude", &config.Params_Traces{ExcludeAttributes: []string{"["}}, "exclude_attributes"},
+		{"empty rename", &config.Params_Traces{RenameAttributes: map[string]string{"": "b"}}, "rename_attributes"},
+	}
+
+	for _, tc := range testCases {
+		_, err := BuildHandler(&config.Params{Traces: tc.traces}, &export.Recorder{}, nil)
+		if tc.expectedErrMsg == "" {
+			if err != nil {
+				t.Errorf("%s: Did not expect error, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg) {
+			t.Errorf("%s: Expected error to contain '%s', got %v", tc.name, tc.expectedErrMsg, err)
+		}
+	}
+}
@@ -16,14 +16,118 @@
 package trace
 
 import (
+	"time"
+
 	"github.com/newrelic/newrelic-istio-adapter/config"
-	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"istio.io/istio/mixer/pkg/adapter"
 )
 
-// BuildHandler returns a trace Handler with valid configuration.
-func BuildHandler(_ *config.Params, h *telemetry.Harvester) (*Handler, error) {
+// BuildHandler returns a trace Handler with valid configuration that
+// records spans with e and, if span metrics are enabled, the metrics
+// derived from them with metrics.
+func BuildHandler(params *config.Params, e export.Exporter, metrics MetricRecorder) (*Handler, error) {
+	errs := validateSampling(params.GetTraceSampling())
+	errs = errs.Extend(validateTraces(params.GetTraces()))
+	errs = errs.Extend(validateIDNormalization(params.GetIdNormalization()))
+	errs = errs.Extend(validatePhases(params.GetSpanPhases()))
+	names, nameErrs := buildServiceNamer(params.GetServiceName())
+	errs = errs.Extend(nameErrs)
+	errs = errs.Extend(validateRateLimit("span_rate_limit", params.GetSpanRateLimit()))
+	for service, l := range params.GetServiceSpanRateLimits() {
+		errs = errs.Extend(validateRateLimit("service_span_rate_limits", l))
+		if service == "" {
+			errs = errs.Appendf("service_span_rate_limits", "service name must be non-empty")
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
+	sampling := params.GetTraceSampling()
 	traceHandler := &Handler{
-		harvester: h,
+		exporter: e,
+		head:     newHeadSampler(sampling.GetHeadPercentage()),
+		limiter:  newRateLimiter(params.GetSpanRateLimit(), params.GetServiceSpanRateLimits()),
+		attrs:    newAttributeMapper(params.GetTraces()),
+		names:    names,
+		marker:   newErrorMarker(params.GetErrorSpans()),
+		ids:      newIDNormalizer(params.GetIdNormalization()),
+		phases:   newPhases(params.GetSpanPhases()),
+	}
+	if sm := params.GetSpanMetrics(); sm != nil && metrics != nil {
+		traceHandler.metrics = newSpanMetrics(spanMetricsNamespace(params), metrics)
+	}
+	if tail := sampling.GetTail(); tail != nil {
+		traceHandler.tail = newTailSampler(tail.Window, tail.LatencyThreshold, int(tail.MaxTraces), traceHandler.head, traceHandler.record)
+		traceHandler.tail.start(tail.Window / 4)
 	}
 	return traceHandler, nil
 }
+
+// minTailWindow is the shortest tail sampling window. Buffered traces are
+// checked a quarter of the window apart.
+const minTailWindow = time.Second
+
+// validateSampling returns any errors found validating the trace sampling
+// configuration.
+func validateSampling(sampling *config.Params_TraceSampling) (errs *adapter.ConfigErrors) {
+	if p := sampling.GetHeadPercentage(); p < 0 || p > 100 {
+		errs = errs.Appendf("TraceSampling.head_percentage", "must be >= 0 and <= 100: %v", p)
+	}
+	tail := sampling.GetTail()
+	if tail == nil {
+		return errs
+	}
+	if tail.Window < minTailWindow {
+		errs = errs.Appendf("TraceSampling.TailSampling.window", "must be >= %v", minTailWindow)
+	}
+	if tail.LatencyThreshold < 0 {
+		errs = errs.Appendf("TraceSampling.TailSampling.latency_threshold", "must be >= 0")
+	}
+	if tail.MaxTraces < 0 {
+		errs = errs.Appendf("TraceSampling.TailSampling.max_traces", "must be >= 0")
+	}
+	return errs
+}
+
+// validateRateLimit returns any errors found validating the rate limit l
+// of field.
+func validateRateLimit(field string, l *config.Params_SpanRateLimit) (errs *adapter.ConfigErrors) {
+	if l.GetSpansPerSecond() < 0 {
+		errs = errs.Appendf(field, "spans_per_second must be >= 0")
+	}
+	if l.GetBurst() < 0 {
+		errs = errs.Appendf(field, "burst must be >= 0")
+	}
+	return errs
+}
+
+// spanMetricsNamespace returns the namespace of the metrics derived from
+// spans.
+func spanMetricsNamespace(params *config.Params) string {
+	if ns := params.GetSpanMetrics().GetNamespace(); ns != "" {
+		return ns
+	}
+	if ns := params.GetNamespace(); ns != "" {
+		return ns + ".span"
+	}
+	return "span"
+}
+
+// validateIDNormalization returns any errors found validating the ID
+// normalization configuration n.
+func validateIDNormalization(n *config.Params_IdNormalization) (errs *adapter.ConfigErrors) {
+	if n == nil {
+		return nil
+	}
+	if _, ok := config.Params_IdNormalization_Format_name[int32(n.Format)]; !ok {
+		errs = errs.Appendf("IdNormalization.format", "unknown format: %v", n.Format)
+	}
+	switch n.TraceIdLength {
+	case 0, spanIDLength, maxTraceIDLength:
+	default:
+		errs = errs.Appendf("IdNormalization.trace_id_length", "must be %d or %d: %d", spanIDLength, maxTraceIDLength, n.TraceIdLength)
+	}
+	return errs
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+)
+
+const (
+	// errorAttribute marks a span as an error in the New Relic trace UI.
+	errorAttribute        = "error"
+	errorMessageAttribute = "error.message"
+	// defaultResponseFlagsTag is the span tag holding the Envoy response
+	// flags if none is configured.
+	defaultResponseFlagsTag = "response.flags"
+)
+
+// errorMarker marks the spans matching its rules as errors.
+type errorMarker struct {
+	clientErrors     bool
+	grpcStatusTag    string
+	responseFlags    map[string]bool
+	responseFlagsTag string
+	messageTag       string
+}
+
+// newErrorMarker returns the errorMarker configured by e.
+func newErrorMarker(e *config.Params_ErrorSpans) errorMarker {
+	m := errorMarker{
+		clientErrors:     e.GetClientErrors(),
+		grpcStatusTag:    e.GetGrpcStatusTag(),
+		responseFlagsTag: e.GetResponseFlagsTag(),
+		messageTag:       e.GetMessageTag(),
+	}
+	if m.responseFlagsTag == "" {
+		m.responseFlagsTag = defaultResponseFlagsTag
+	}
+	if len(e.GetResponseFlags()) > 0 {
+		m.responseFlags = make(map[string]bool, len(e.GetResponseFlags()))
+		for _, f := range e.GetResponseFlags() {
+			m.responseFlags[f] = true
+		}
+	}
+	return m
+}
+
+// isError returns whether a span with attrs and the HTTP statusCode is an
+// error.
+func (m errorMarker) isError(attrs map[string]interface{}, statusCode int64) bool {
+	if statusCode >= 500 || (m.clientErrors && statusCode >= 400) {
+		return true
+	}
+	if m.grpcStatusTag != "" {
+		if v, ok := attrs[m.grpcStatusTag]; ok {
+			switch s := fmt.Sprint(v); s {
+			case "", "0", "OK":
+			default:
+				return true
+			}
+		}
+	}
+	if m.responseFlags != nil {
+		if v, ok := attrs[m.responseFlagsTag].(string); ok {
+			for _, f := range strings.Split(v, ",") {
+				if m.responseFlags[strings.TrimSpace(f)] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// mark sets the error attributes of attrs, the attributes of a span with
+// the HTTP statusCode, if it is an error. It returns whether it is.
+func (m errorMarker) mark(attrs map[string]interface{}, statusCode int64) bool {
+	if !m.isError(attrs, statusCode) {
+		return false
+	}
+	attrs[errorAttribute] = true
+	if m.messageTag == "" {
+		return true
+	}
+	if v, ok := attrs[m.messageTag]; ok {
+		if msg := fmt.Sprint(v); msg != "" {
+			attrs[errorMessageAttribute] = msg
+		}
+	}
+	return true
+}
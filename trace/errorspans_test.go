@@ -0,0 +1,159 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/tracespan"
+)
+
+func TestErrorMarker(t *testing.T) {
+	all := &config.Params_ErrorSpans{
+		ClientErrors:  true,
+		GrpcStatusTag: "response.grpc_status",
+		ResponseFlags: []string{"UH", "URX"},
+		MessageTag:    "response.details",
+	}
+
+	testCases := []struct {
+		name       string
+		errorSpans *config.Params_ErrorSpans
+		attrs      map[string]interface{}
+		statusCode int64
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "ok",
+			statusCode: 200,
+			attrs:      map[string]interface{}{},
+			expected:   map[string]interface{}{},
+		},
+		{
+			name:       "server error by default",
+			statusCode: 503,
+			attrs:      map[string]interface{}{"response.details": "upstream_reset"},
+			expected:   map[string]interface{}{"response.details": "upstream_reset", "error": true},
+		},
+		{
+			name:       "client error not marked by default",
+			statusCode: 404,
+			attrs:      map[string]interface{}{},
+			expected:   map[string]interface{}{},
+		},
+		{
+			name:       "client error",
+			errorSpans: all,
+			statusCode: 404,
+			attrs:      map[string]interface{}{"response.details": "route_not_found"},
+			expected: map[string]interface{}{
+				"response.details": "route_not_found",
+				"error":            true,
+				"error.message":    "route_not_found",
+			},
+		},
+		{
+			name:       "grpc ok",
+			errorSpans: all,
+			statusCode: 200,
+			attrs:      map[string]interface{}{"response.grpc_status": "0"},
+			expected:   map[string]interface{}{"response.grpc_status": "0"},
+		},
+		{
+			name:       "grpc error",
+			errorSpans: all,
+			statusCode: 200,
+			attrs:      map[string]interface{}{"response.grpc_status": "14"},
+			expected:   map[string]interface{}{"response.grpc_status": "14", "error": true},
+		},
+		{
+			name:       "response flag",
+			errorSpans: all,
+			statusCode: 200,
+			attrs:      map[string]interface{}{"response.flags": "DC,URX"},
+			expected:   map[string]interface{}{"response.flags": "DC,URX", "error": true},
+		},
+		{
+			name:       "unconfigured response flag",
+			errorSpans: all,
+			statusCode: 200,
+			attrs:      map[string]interface{}{"response.flags": "-"},
+			expected:   map[string]interface{}{"response.flags": "-"},
+		},
+		{
+			name:       "response flags tag",
+			errorSpans: &config.Params_ErrorSpans{ResponseFlags: []string{"UH"}, ResponseFlagsTag: "flags"},
+			statusCode: 200,
+			attrs:      map[string]interface{}{"flags": "UH", "response.flags": "-"},
+			expected:   map[string]interface{}{"flags": "UH", "response.flags": "-", "error": true},
+		},
+	}
+
+	for _, tc := range testCases {
+		newErrorMarker(tc.errorSpans).mark(tc.attrs, tc.statusCode)
+		if !reflect.DeepEqual(tc.attrs, tc.expected) {
+			t.Errorf("%s: expected %#v, got %#v", tc.name, tc.expected, tc.attrs)
+		}
+	}
+}
+
+func TestTailSamplingKeepsErrorSpans(t *testing.T) {
+	timestamp, err := types.TimestampProto(time.Now())
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+	msg := func(traceID string, code int64) *tracespan.InstanceMsg {
+		return &tracespan.InstanceMsg{
+			SpanId:          "span",
+			TraceId:         traceID,
+			StartTime:       &policy.TimeStamp{Value: timestamp},
+			EndTime:         &policy.TimeStamp{Value: timestamp},
+			DestinationName: "backend",
+			HttpStatusCode:  code,
+		}
+	}
+
+	recorder := &export.Recorder{}
+	h, err := BuildHandler(&config.Params{
+		TraceSampling: &config.Params_TraceSampling{
+			HeadPercentage: 1e-9,
+			Tail:           &config.Params_TraceSampling_TailSampling{Window: time.Minute},
+		},
+		ErrorSpans: &config.Params_ErrorSpans{ClientErrors: true},
+	}, recorder, nil)
+	if err != nil {
+		t.Fatalf("failed to build handler: %v", err)
+	}
+	err = h.HandleTraceSpan(context.Background(), []*tracespan.InstanceMsg{
+		msg("client-error", 404),
+		msg("ok", 200),
+	})
+	if err != nil {
+		t.Fatalf("HandleTraceSpan errored: %v", err)
+	}
+	h.Close()
+
+	spans := recorder.Spans()
+	if len(spans) != 1 || spans[0].TraceID != "client-error" {
+		t.Errorf("expected only the client error trace kept, got %v", spans)
+	}
+}
@@ -18,18 +18,35 @@ package trace
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/gogo/protobuf/types"
 	"github.com/newrelic/newrelic-istio-adapter/convert"
+	"github.com/newrelic/newrelic-istio-adapter/export"
 	"github.com/newrelic/newrelic-istio-adapter/log"
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	"istio.io/istio/mixer/pkg/adapter"
 	"istio.io/istio/mixer/template/tracespan"
 )
 
+var (
+	errNoTraceID = errors.New("no trace ID")
+	errNoSpanID  = errors.New("no span ID")
+)
+
 // Handler represents a processor that can handle tracespans from Istio and transmit them to New Relic.
 type Handler struct {
-	harvester *telemetry.Harvester
+	exporter export.Exporter
+	head     headSampler
+	tail     *tailSampler
+	limiter  *rateLimiter
+	metrics  *spanMetrics
+	attrs    *attributeMapper
+	names    *serviceNamer
+	marker   errorMarker
+	ids      *idNormalizer
+	phases   []phase
 }
 
 // HandleTraceSpan transforms tracespan template instances into New Relic spans and
@@ -38,15 +55,81 @@ func (h *Handler) HandleTraceSpan(_ context.Context, msgs []*tracespan.InstanceM
 	for _, i := range msgs {
 		span, err := convertTraceSpan(i)
 		if err != nil {
+			selfmetric.RecordConversionError(selfmetric.TraceSpanTemplate, conversionErrorReason(err))
+			log.Warnf("error converting tracespan: %v", err)
+			continue
+		}
+		if err := h.ids.normalize(span); err != nil {
+			selfmetric.RecordConversionError(selfmetric.TraceSpanTemplate, conversionErrorReason(err))
 			log.Warnf("error converting tracespan: %v", err)
 			continue
 		}
+		span.ServiceName = h.names.resolve(span.Attributes, i.ClientSpan, span.ServiceName)
+		isError := h.marker.mark(span.Attributes, i.HttpStatusCode)
+		span.Attributes = h.attrs.apply(span.Attributes, i.ClientSpan)
 
-		h.harvester.RecordSpan(*span)
+		if h.metrics != nil {
+			h.metrics.record(*span, i.HttpStatusCode, isError, i.ClientSpan)
+		}
+
+		h.sample(*span, isError)
+		for _, p := range derivePhases(h.phases, *span, i.SpanTags) {
+			h.sample(p, isError)
+		}
 	}
 	return nil
 }
 
+// sample records span, of a request that isError, if its trace is
+// sampled.
+func (h *Handler) sample(span telemetry.Span, isError bool) {
+	switch {
+	case h.tail != nil:
+		h.tail.add(span, isError, time.Now())
+	case h.head.keep(span.TraceID):
+		h.record(span)
+	default:
+		selfmetric.RecordSpansDropped("sampled", 1)
+	}
+}
+
+// record records span with the exporter, unless its service exceeded its
+// rate limit.
+func (h *Handler) record(span telemetry.Span) {
+	if h.limiter != nil && !h.limiter.allow(span.ServiceName, time.Now()) {
+		selfmetric.RecordSpanRateLimited(h.limiter.labels(span.ServiceName))
+		return
+	}
+	if err := h.exporter.RecordSpan(span); err != nil {
+		selfmetric.RecordConversionError(selfmetric.TraceSpanTemplate, "record_failed")
+		log.Warnf("error recording span: %v", err)
+	}
+}
+
+// Close records the spans of the traces buffered for tail sampling that
+// are kept.
+func (h *Handler) Close() {
+	if h.tail != nil {
+		h.tail.close()
+	}
+}
+
+// conversionErrorReason returns the self metric reason of the error
+// returned by convertTraceSpan or idNormalizer.normalize.
+func conversionErrorReason(err error) string {
+	if e, ok := err.(*idError); ok {
+		return e.role.reason()
+	}
+	switch err {
+	case errNoTraceID:
+		return "missing_trace_id"
+	case errNoSpanID:
+		return "missing_span_id"
+	default:
+		return "invalid_timestamp"
+	}
+}
+
 // convertTraceSpan will convert a tracespan.InstanceMsg into a telemetry.Span.
 func convertTraceSpan(i *tracespan.InstanceMsg) (*telemetry.Span, error) {
 	startTime, err := types.TimestampFromProto(i.StartTime.GetValue())
@@ -59,11 +142,11 @@ func convertTraceSpan(i *tracespan.InstanceMsg) (*telemetry.Span, error) {
 	}
 
 	if i.TraceId == "" {
-		return nil, errors.New("no trace ID")
+		return nil, errNoTraceID
 	}
 
 	if i.SpanId == "" {
-		return nil, errors.New("no span ID")
+		return nil, errNoSpanID
 	}
 
 	attributes := convert.DimensionsToAttributes(i.SpanTags)
@@ -15,11 +15,13 @@
 package trace
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/export"
 	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
 	policy "istio.io/api/policy/v1beta1"
 	"istio.io/istio/mixer/template/tracespan"
@@ -133,3 +135,39 @@ func TestConvertTraceSpan(t *testing.T) {
 		t.Errorf("expected attributes '%#v', got '%#v'", expected.Attributes, actual.Attributes)
 	}
 }
+
+func TestHandleTraceSpan(t *testing.T) {
+	timestamp, err := types.TimestampProto(time.Date(2020, time.August, 10, 11, 12, 30, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+
+	msgs := []*tracespan.InstanceMsg{
+		{
+			SpanId:    "span",
+			TraceId:   "trace",
+			StartTime: &policy.TimeStamp{Value: timestamp},
+			EndTime:   &policy.TimeStamp{Value: timestamp},
+		},
+		{
+			// Spans without a trace ID are dropped.
+			SpanId:    "dropped",
+			StartTime: &policy.TimeStamp{Value: timestamp},
+			EndTime:   &policy.TimeStamp{Value: timestamp},
+		},
+	}
+
+	recorder := &export.Recorder{}
+	h, err := BuildHandler(nil, recorder, nil)
+	if err != nil {
+		t.Fatalf("failed to build handler: %v", err)
+	}
+	if err := h.HandleTraceSpan(context.Background(), msgs); err != nil {
+		t.Fatalf("HandleTraceSpan errored: %v", err)
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 1 || spans[0].ID != "span" {
+		t.Errorf("expected span %q to be recorded, got %#v", "span", spans)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+const (
+	// spanIDLength is the length of span IDs in hex digits.
+	spanIDLength = 16
+	// maxTraceIDLength is the length of 128-bit trace IDs in hex digits.
+	maxTraceIDLength = 32
+)
+
+// idRole is the span ID an ID is used as.
+type idRole int
+
+const (
+	traceIDRole idRole = iota
+	spanIDRole
+	parentIDRole
+)
+
+// String returns the name of the ID used in error messages.
+func (r idRole) String() string {
+	switch r {
+	case traceIDRole:
+		return "trace ID"
+	case spanIDRole:
+		return "span ID"
+	default:
+		return "parent span ID"
+	}
+}
+
+// reason returns the self metric reason of a malformed ID.
+func (r idRole) reason() string {
+	switch r {
+	case traceIDRole:
+		return "malformed_trace_id"
+	case spanIDRole:
+		return "malformed_span_id"
+	default:
+		return "malformed_parent_id"
+	}
+}
+
+// idError is returned for a malformed ID.
+type idError struct {
+	role idRole
+	id   string
+	msg  string
+}
+
+func (e *idError) Error() string {
+	return fmt.Sprintf("malformed %s %q: %s", e.role, e.id, e.msg)
+}
+
+// idNormalizer validates and canonicalizes the IDs of spans.
+type idNormalizer struct {
+	format        config.Params_IdNormalization_Format
+	traceIDLength int
+}
+
+// newIDNormalizer returns the idNormalizer configured by c, or nil if IDs
+// are sent as received.
+func newIDNormalizer(c *config.Params_IdNormalization) *idNormalizer {
+	if c == nil {
+		return nil
+	}
+	return &idNormalizer{format: c.Format, traceIDLength: int(c.TraceIdLength)}
+}
+
+// normalize canonicalizes the trace, span and parent span IDs of span. It
+// returns an *idError if one is malformed.
+func (n *idNormalizer) normalize(span *telemetry.Span) error {
+	if n == nil {
+		return nil
+	}
+	traceID, err := n.id(span.TraceID, traceIDRole)
+	if err != nil {
+		return err
+	}
+	spanID, err := n.id(span.ID, spanIDRole)
+	if err != nil {
+		return err
+	}
+	var parentID string
+	if span.ParentID != "" {
+		if parentID, err = n.id(span.ParentID, parentIDRole); err != nil {
+			return err
+		}
+	}
+	span.TraceID, span.ID, span.ParentID = traceID, spanID, parentID
+	return nil
+}
+
+// id returns the canonical hex ID of role in v.
+func (n *idNormalizer) id(v string, role idRole) (string, error) {
+	hex, err := n.extract(v, role)
+	if err != nil {
+		return "", &idError{role: role, id: v, msg: err.Error()}
+	}
+	if role == parentIDRole && hex == "" {
+		// The B3 single header does not always have a parent span ID.
+		return "", nil
+	}
+	if hex, err = n.canonicalize(hex, role); err != nil {
+		return "", &idError{role: role, id: v, msg: err.Error()}
+	}
+	return hex, nil
+}
+
+// extract returns the hex ID of role in v, in the configured format.
+func (n *idNormalizer) extract(v string, role idRole) (string, error) {
+	format := n.format
+	if format == config.AUTO {
+		format = detectFormat(v)
+	}
+	switch format {
+	case config.B3_SINGLE:
+		return extractB3(v, role)
+	case config.TRACEPARENT:
+		return extractTraceparent(v, role)
+	default:
+		return v, nil
+	}
+}
+
+// detectFormat returns the format of the ID v.
+func detectFormat(v string) config.Params_IdNormalization_Format {
+	fields := strings.Split(v, "-")
+	switch {
+	case len(fields) == 1:
+		return config.HEX
+	case len(fields) == 4 && len(fields[0]) == 2 && len(fields[1]) == maxTraceIDLength:
+		return config.TRACEPARENT
+	default:
+		return config.B3_SINGLE
+	}
+}
+
+// extractB3 returns the ID of role in the B3 single header v.
+func extractB3(v string, role idRole) (string, error) {
+	fields := strings.Split(v, "-")
+	if len(fields) < 2 || len(fields) > 4 {
+		return "", fmt.Errorf("B3 header must have 2 to 4 fields, got %d", len(fields))
+	}
+	if len(fields) > 2 {
+		switch fields[2] {
+		case "0", "1", "d":
+		default:
+			return "", fmt.Errorf("invalid B3 sampling state %q", fields[2])
+		}
+	}
+	switch role {
+	case traceIDRole:
+		return fields[0], nil
+	case spanIDRole:
+		return fields[1], nil
+	default:
+		if len(fields) < 4 {
+			return "", nil
+		}
+		return fields[3], nil
+	}
+}
+
+// extractTraceparent returns the ID of role in the W3C traceparent header
+// v.
+func extractTraceparent(v string, role idRole) (string, error) {
+	fields := strings.Split(v, "-")
+	if len(fields) < 4 {
+		return "", fmt.Errorf("traceparent header must have 4 fields, got %d", len(fields))
+	}
+	version := fields[0]
+	if len(version) != 2 || !isHex(version) || version == "ff" {
+		return "", fmt.Errorf("invalid traceparent version %q", version)
+	}
+	// Versions after 00 may append fields.
+	if version == "00" && len(fields) != 4 {
+		return "", fmt.Errorf("traceparent header must have 4 fields, got %d", len(fields))
+	}
+	if len(fields[1]) != maxTraceIDLength || len(fields[2]) != spanIDLength {
+		return "", fmt.Errorf("traceparent IDs must have %d and %d hex digits", maxTraceIDLength, spanIDLength)
+	}
+	if len(fields[3]) != 2 || !isHex(fields[3]) {
+		return "", fmt.Errorf("invalid traceparent flags %q", fields[3])
+	}
+	if role == traceIDRole {
+		return fields[1], nil
+	}
+	return fields[2], nil
+}
+
+// canonicalize returns the hex ID of role as lower case hex of its
+// canonical length.
+func (n *idNormalizer) canonicalize(hex string, role idRole) (string, error) {
+	hex = strings.ToLower(hex)
+	if hex == "" || !isHex(hex) {
+		return "", fmt.Errorf("must be hex")
+	}
+	if strings.Trim(hex, "0") == "" {
+		return "", fmt.Errorf("must not be all zeros")
+	}
+
+	length := spanIDLength
+	if role == traceIDRole {
+		if len(hex) > maxTraceIDLength {
+			return "", fmt.Errorf("must have at most %d hex digits", maxTraceIDLength)
+		}
+		length = n.traceIDLength
+		if length == 0 {
+			length = spanIDLength
+			if len(hex) > spanIDLength {
+				length = maxTraceIDLength
+			}
+		}
+	} else if len(hex) > spanIDLength {
+		return "", fmt.Errorf("must have at most %d hex digits", spanIDLength)
+	}
+
+	if len(hex) > length {
+		// 64-bit trace IDs are the rightmost digits of 128-bit ones.
+		return hex[len(hex)-length:], nil
+	}
+	return strings.Repeat("0", length-len(hex)) + hex, nil
+}
+
+// isHex returns whether s only has hex digits.
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
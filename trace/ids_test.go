@@ -0,0 +1,203 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/tracespan"
+)
+
+func TestIDNormalizer(t *testing.T) {
+	const (
+		traceID128 = "463ac35c9f6413ad48485a3953bb6124"
+		traceID64  = "48485a3953bb6124"
+		spanID     = "a2fb4a1d1a96d312"
+		parentID   = "0020000000000001"
+	)
+
+	testCases := []struct {
+		name           string
+		normalization  config.Params_IdNormalization
+		span           telemetry.Span
+		expected       telemetry.Span
+		expectedReason string
+	}{
+		{
+			name:     "hex",
+			span:     telemetry.Span{TraceID: strings.ToUpper(traceID128), ID: spanID, ParentID: parentID},
+			expected: telemetry.Span{TraceID: traceID128, ID: spanID, ParentID: parentID},
+		},
+		{
+			name:     "hex padding",
+			span:     telemetry.Span{TraceID: "abc", ID: "1"},
+			expected: telemetry.Span{TraceID: "0000000000000abc", ID: "0000000000000001"},
+		},
+		{
+			name:     "hex 96-bit trace ID",
+			span:     telemetry.Span{TraceID: "1" + traceID64 + "000", ID: spanID},
+			expected: telemetry.Span{TraceID: "0000000000001" + traceID64 + "000", ID: spanID},
+		},
+		{
+			name:          "truncated trace ID",
+			normalization: config.Params_IdNormalization{TraceIdLength: 16},
+			span:          telemetry.Span{TraceID: traceID128, ID: spanID},
+			expected:      telemetry.Span{TraceID: traceID64, ID: spanID},
+		},
+		{
+			name:          "padded trace ID",
+			normalization: config.Params_IdNormalization{TraceIdLength: 32},
+			span:          telemetry.Span{TraceID: traceID64, ID: spanID},
+			expected:      telemetry.Span{TraceID: "0000000000000000" + traceID64, ID: spanID},
+		},
+		{
+			name: "b3 single",
+			span: telemetry.Span{
+				TraceID:  traceID64 + "-" + spanID + "-1-" + parentID,
+				ID:       traceID64 + "-" + spanID + "-1-" + parentID,
+				ParentID: traceID64 + "-" + spanID + "-1-" + parentID,
+			},
+			expected: telemetry.Span{TraceID: traceID64, ID: spanID, ParentID: parentID},
+		},
+		{
+			name: "b3 single without parent",
+			span: telemetry.Span{
+				TraceID:  traceID128 + "-" + spanID,
+				ID:       traceID128 + "-" + spanID,
+				ParentID: traceID128 + "-" + spanID,
+			},
+			expected: telemetry.Span{TraceID: traceID128, ID: spanID},
+		},
+		{
+			name: "traceparent",
+			span: telemetry.Span{
+				TraceID: "00-" + traceID128 + "-" + spanID + "-01",
+				ID:      "00-" + traceID128 + "-" + spanID + "-01",
+			},
+			expected: telemetry.Span{TraceID: traceID128, ID: spanID},
+		},
+		{
+			name:           "hex format rejects headers",
+			normalization:  config.Params_IdNormalization{Format: config.HEX},
+			span:           telemetry.Span{TraceID: traceID64 + "-" + spanID, ID: spanID},
+			expectedReason: "malformed_trace_id",
+		},
+		{
+			name:           "traceparent format",
+			normalization:  config.Params_IdNormalization{Format: config.TRACEPARENT},
+			span:           telemetry.Span{TraceID: traceID64 + "-" + spanID, ID: spanID},
+			expectedReason: "malformed_trace_id",
+		},
+		{
+			name:           "invalid traceparent version",
+			span:           telemetry.Span{TraceID: "ff-" + traceID128 + "-" + spanID + "-01", ID: spanID},
+			expectedReason: "malformed_trace_id",
+		},
+		{
+			name:           "invalid b3 sampling state",
+			span:           telemetry.Span{TraceID: traceID64, ID: traceID64 + "-" + spanID + "-x"},
+			expectedReason: "malformed_span_id",
+		},
+		{
+			name:           "trace ID too long",
+			span:           telemetry.Span{TraceID: "1" + traceID128, ID: spanID},
+			expectedReason: "malformed_trace_id",
+		},
+		{
+			name:           "span ID too long",
+			span:           telemetry.Span{TraceID: traceID64, ID: "1" + spanID},
+			expectedReason: "malformed_span_id",
+		},
+		{
+			name:           "not hex",
+			span:           telemetry.Span{TraceID: traceID64, ID: spanID, ParentID: "parent"},
+			expectedReason: "malformed_parent_id",
+		},
+		{
+			name:           "all zeros",
+			span:           telemetry.Span{TraceID: "0000000000000000", ID: spanID},
+			expectedReason: "malformed_trace_id",
+		},
+	}
+
+	for _, tc := range testCases {
+		span := tc.span
+		err := newIDNormalizer(&tc.normalization).normalize(&span)
+		if tc.expectedReason != "" {
+			if err == nil || conversionErrorReason(err) != tc.expectedReason {
+				t.Errorf("%s: expected %s error, got %v", tc.name, tc.expectedReason, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Did not expect error, got %v", tc.name, err)
+			continue
+		}
+		if span.TraceID != tc.expected.TraceID || span.ID != tc.expected.ID || span.ParentID != tc.expected.ParentID {
+			t.Errorf("%s: expected IDs %s/%s/%s, got %s/%s/%s", tc.name,
+				tc.expected.TraceID, tc.expected.ID, tc.expected.ParentID, span.TraceID, span.ID, span.ParentID)
+		}
+	}
+}
+
+func TestHandleTraceSpanIDNormalization(t *testing.T) {
+	timestamp, err := types.TimestampProto(time.Now())
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+	msg := func(traceID, spanID string) *tracespan.InstanceMsg {
+		return &tracespan.InstanceMsg{
+			TraceId:   traceID,
+			SpanId:    spanID,
+			StartTime: &policy.TimeStamp{Value: timestamp},
+			EndTime:   &policy.TimeStamp{Value: timestamp},
+		}
+	}
+
+	recorder := &export.Recorder{}
+	h, err := BuildHandler(&config.Params{IdNormalization: &config.Params_IdNormalization{}}, recorder, nil)
+	if err != nil {
+		t.Fatalf("failed to build handler: %v", err)
+	}
+	err = h.HandleTraceSpan(context.Background(), []*tracespan.InstanceMsg{
+		msg("ABC", "1"),
+		msg("trace", "1"),
+	})
+	if err != nil {
+		t.Fatalf("HandleTraceSpan errored: %v", err)
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 1 || spans[0].TraceID != "0000000000000abc" || spans[0].ID != "0000000000000001" {
+		t.Errorf("expected only the normalized span recorded, got %#v", spans)
+	}
+
+	for _, n := range []*config.Params_IdNormalization{
+		{TraceIdLength: 24},
+		{Format: config.Params_IdNormalization_Format(7)},
+	} {
+		if _, err := BuildHandler(&config.Params{IdNormalization: n}, recorder, nil); err == nil || !strings.Contains(err.Error(), "IdNormalization") {
+			t.Errorf("%v: Expected error to contain '%s', got %v", n, "IdNormalization", err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/convert"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/pkg/adapter"
+)
+
+// phaseAttribute is the attribute holding the name of the phase of a
+// phase span.
+const phaseAttribute = "phase"
+
+// nanosecond is the unit duration and numeric tag values are read in.
+var nanosecond, _ = convert.ParseUnit("ns")
+
+// phase is a phase of a request whose start and end are read from span
+// tags. An empty tag is the start or end of the request.
+type phase struct {
+	name     string
+	startTag string
+	endTag   string
+}
+
+// newPhases returns the phases configured by c.
+func newPhases(c []*config.Params_SpanPhase) []phase {
+	phases := make([]phase, 0, len(c))
+	for _, p := range c {
+		phases = append(phases, phase{name: p.Name, startTag: p.StartTag, endTag: p.EndTag})
+	}
+	return phases
+}
+
+// validatePhases returns any errors found validating the span phase
+// configuration.
+func validatePhases(c []*config.Params_SpanPhase) (errs *adapter.ConfigErrors) {
+	names := make(map[string]bool, len(c))
+	for _, p := range c {
+		if p.GetName() == "" {
+			errs = errs.Appendf("SpanPhase.name", "name must be non-empty")
+		} else if names[p.Name] {
+			errs = errs.Appendf("SpanPhase.name", "duplicate phase %q", p.Name)
+		}
+		names[p.GetName()] = true
+		if p.GetStartTag() == "" && p.GetEndTag() == "" {
+			errs = errs.Appendf("SpanPhase", "phase %q must have a start_tag or end_tag", p.GetName())
+		}
+	}
+	return errs
+}
+
+// derivePhases returns the phase spans of span, the span of a request
+// with the span tags. A phase whose tags are missing, invalid or which
+// does not overlap the request is skipped.
+func derivePhases(phases []phase, span telemetry.Span, tags map[string]*policy.Value) []telemetry.Span {
+	var spans []telemetry.Span
+	end := span.Timestamp.Add(span.Duration)
+	for _, p := range phases {
+		phaseStart, ok := tagTime(tags, p.startTag, span.Timestamp, span.Timestamp)
+		if !ok {
+			continue
+		}
+		phaseEnd, ok := tagTime(tags, p.endTag, span.Timestamp, end)
+		if !ok {
+			continue
+		}
+		if phaseStart.Before(span.Timestamp) {
+			phaseStart = span.Timestamp
+		}
+		if phaseEnd.After(end) {
+			phaseEnd = end
+		}
+		if phaseEnd.Before(phaseStart) {
+			continue
+		}
+
+		spans = append(spans, telemetry.Span{
+			ID:          phaseSpanID(span.ID, p.name),
+			TraceID:     span.TraceID,
+			Name:        p.name,
+			ParentID:    span.ID,
+			Timestamp:   phaseStart,
+			Duration:    phaseEnd.Sub(phaseStart),
+			ServiceName: span.ServiceName,
+			Attributes:  map[string]interface{}{phaseAttribute: p.name},
+		})
+	}
+	return spans
+}
+
+// tagTime returns the time held by the span tag, relative to start for
+// duration and numeric values, or def if tag is empty. It returns false if
+// the tag is missing or its value is not a time.
+func tagTime(tags map[string]*policy.Value, tag string, start, def time.Time) (time.Time, bool) {
+	if tag == "" {
+		return def, true
+	}
+	v, ok := tags[tag]
+	if !ok {
+		return time.Time{}, false
+	}
+	if ts, ok := v.GetValue().(*policy.Value_TimestampValue); ok {
+		t, err := types.TimestampFromProto(ts.TimestampValue.GetValue())
+		return t, err == nil
+	}
+	nanos, err := convert.ValueToUnit(v, nanosecond)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return start.Add(time.Duration(nanos)), true
+}
+
+// phaseSpanID returns the ID of the phase span of the span with spanID, a
+// 64-bit hex ID so it is valid wherever the ID of its parent is.
+func phaseSpanID(spanID, phase string) string {
+	h := fnv.New64a()
+	h.Write([]byte(spanID))
+	h.Write([]byte{0})
+	h.Write([]byte(phase))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/tracespan"
+)
+
+func durationValue(d time.Duration) *policy.Value {
+	return &policy.Value{Value: &policy.Value_DurationValue{DurationValue: &policy.Duration{Value: types.DurationProto(d)}}}
+}
+
+func timestamp(t *testing.T, ts time.Time) *policy.TimeStamp {
+	p, err := types.TimestampProto(ts)
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+	return &policy.TimeStamp{Value: p}
+}
+
+func TestDerivePhases(t *testing.T) {
+	start := time.Date(2020, time.August, 10, 11, 12, 30, 0, time.UTC)
+	span := telemetry.Span{
+		ID:          "a2fb4a1d1a96d312",
+		TraceID:     "48485a3953bb6124",
+		Timestamp:   start,
+		Duration:    100 * time.Millisecond,
+		ServiceName: "reviews",
+	}
+	tags := map[string]*policy.Value{
+		"upstream.connect": durationValue(5 * time.Millisecond),
+		"upstream.ttfb":    {Value: &policy.Value_Int64Value{Int64Value: 40}},
+		"upstream.tls":     {Value: &policy.Value_TimestampValue{TimestampValue: timestamp(t, start.Add(8*time.Millisecond))}},
+		"late":             durationValue(time.Second),
+		"name":             {Value: &policy.Value_StringValue{StringValue: "reviews"}},
+	}
+
+	phases := newPhases([]*config.Params_SpanPhase{
+		{Name: "connect", EndTag: "upstream.connect"},
+		{Name: "tls", StartTag: "upstream.connect", EndTag: "upstream.tls"},
+		{Name: "ttfb", StartTag: "upstream.tls", EndTag: "upstream.ttfb"},
+		{Name: "transfer", StartTag: "upstream.ttfb"},
+		{Name: "clamped", StartTag: "upstream.ttfb", EndTag: "late"},
+		{Name: "inverted", StartTag: "upstream.ttfb", EndTag: "upstream.connect"},
+		{Name: "missing", EndTag: "upstream.dns"},
+		{Name: "invalid", EndTag: "name"},
+	})
+
+	expected := []struct {
+		name  string
+		start time.Duration
+		end   time.Duration
+	}{
+		{"connect", 0, 5 * time.Millisecond},
+		{"tls", 5 * time.Millisecond, 8 * time.Millisecond},
+		{"ttfb", 8 * time.Millisecond, 40 * time.Millisecond},
+		{"transfer", 40 * time.Millisecond, 100 * time.Millisecond},
+		{"clamped", 40 * time.Millisecond, 100 * time.Millisecond},
+	}
+
+	got := derivePhases(phases, span, tags)
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d phase spans, got %#v", len(expected), got)
+	}
+	ids := make(map[string]bool)
+	for i, e := range expected {
+		p := got[i]
+		if p.Name != e.name || p.Timestamp != start.Add(e.start) || p.Duration != e.end-e.start {
+			t.Errorf("expected %s phase from %v to %v, got %s at %v for %v", e.name, e.start, e.end, p.Name, p.Timestamp.Sub(start), p.Duration)
+		}
+		if p.TraceID != span.TraceID || p.ParentID != span.ID || p.ServiceName != span.ServiceName {
+			t.Errorf("%s: expected child span of %#v, got %#v", e.name, span, p)
+		}
+		if p.Attributes[phaseAttribute] != e.name {
+			t.Errorf("%s: expected %s attribute, got %#v", e.name, phaseAttribute, p.Attributes)
+		}
+		if len(p.ID) != spanIDLength || ids[p.ID] {
+			t.Errorf("%s: expected unique 16 digit span ID, got %q", e.name, p.ID)
+		}
+		ids[p.ID] = true
+	}
+}
+
+func TestHandleTraceSpanPhases(t *testing.T) {
+	start := time.Now()
+	msg := &tracespan.InstanceMsg{
+		SpanId:    "span",
+		TraceId:   "trace",
+		StartTime: timestamp(t, start),
+		EndTime:   timestamp(t, start.Add(time.Second)),
+		SpanTags: map[string]*policy.Value{
+			"upstream.connect": durationValue(5 * time.Millisecond),
+		},
+	}
+
+	recorder := &export.Recorder{}
+	h, err := BuildHandler(&config.Params{
+		SpanPhases: []*config.Params_SpanPhase{{Name: "connect", EndTag: "upstream.connect"}},
+	}, recorder, nil)
+	if err != nil {
+		t.Fatalf("failed to build handler: %v", err)
+	}
+	if err := h.HandleTraceSpan(context.Background(), []*tracespan.InstanceMsg{msg}); err != nil {
+		t.Fatalf("HandleTraceSpan errored: %v", err)
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 2 || spans[0].ID != "span" || spans[1].Name != "connect" || spans[1].ParentID != "span" {
+		t.Errorf("expected span and connect phase span recorded, got %#v", spans)
+	}
+}
+
+func TestBuildHandlerPhases(t *testing.T) {
+	testCases := []struct {
+		name           string
+		phases         []*config.Params_SpanPhase
+		expectedErrMsg string
+	}{
+		{"none", nil, ""},
+		{"valid", []*config.Params_SpanPhase{{Name: "connect", EndTag: "connect"}, {Name: "transfer", StartTag: "ttfb"}}, ""},
+		{"no name", []*config.Params_SpanPhase{{EndTag: "connect"}}, "non-empty"},
+		{"duplicate", []*config.Params_SpanPhase{{Name: "connect", EndTag: "a"}, {Name: "connect", EndTag: "b"}}, "duplicate"},
+		{"no tags", []*config.Params_SpanPhase{{Name: "connect"}}, "start_tag or end_tag"},
+	}
+
+	for _, tc := range testCases {
+		_, err := BuildHandler(&config.Params{SpanPhases: tc.phases}, &export.Recorder{}, nil)
+		if tc.expectedErrMsg == "" {
+			if err != nil {
+				t.Errorf("%s: Did not expect error, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg) {
+			t.Errorf("%s: Expected error to contain '%s', got %v", tc.name, tc.expectedErrMsg, err)
+		}
+	}
+}
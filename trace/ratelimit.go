@@ -0,0 +1,170 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+)
+
+// rateLimit is the sustained rate and burst of a token bucket. A zero
+// rate is not limited.
+type rateLimit struct {
+	rate  float64
+	burst float64
+}
+
+// newRateLimit returns the rateLimit configured by l.
+func newRateLimit(l *config.Params_SpanRateLimit) rateLimit {
+	rate := l.GetSpansPerSecond()
+	burst := float64(l.GetBurst())
+	if burst == 0 {
+		burst = math.Ceil(rate)
+	}
+	return rateLimit{rate: rate, burst: burst}
+}
+
+// tokenBucket holds the tokens available to a service. A span takes one
+// token.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// pruneInterval is how often the buckets that have refilled are dropped.
+const pruneInterval = time.Minute
+
+// otherServices is the service label of the rate limited spans of the
+// services not reported by name.
+const otherServices = "other"
+
+// maxReportedServices is the most services the rate limited spans of are
+// reported by name each pruneInterval. The spans of further services are
+// reported as otherServices.
+const maxReportedServices = 100
+
+// rateLimiter limits the spans of each service with a token bucket.
+type rateLimiter struct {
+	def       rateLimit
+	overrides map[string]rateLimit
+
+	lock       sync.Mutex
+	buckets    map[string]*tokenBucket
+	lastPruned time.Time
+	// reported holds the services rate limited spans were reported for by
+	// name since lastPruned.
+	reported map[string]struct{}
+}
+
+// newRateLimiter returns a rateLimiter applying def to the spans of each
+// service, unless the service has a limit in overrides. It returns nil if
+// no service is limited.
+func newRateLimiter(def *config.Params_SpanRateLimit, overrides map[string]*config.Params_SpanRateLimit) *rateLimiter {
+	l := &rateLimiter{
+		def:       newRateLimit(def),
+		overrides: make(map[string]rateLimit, len(overrides)),
+		buckets:   make(map[string]*tokenBucket),
+		reported:  make(map[string]struct{}),
+	}
+	limited := l.def.rate > 0
+	for service, o := range overrides {
+		l.overrides[service] = newRateLimit(o)
+		limited = limited || o.GetSpansPerSecond() > 0
+	}
+	if !limited {
+		return nil
+	}
+	return l
+}
+
+// limit returns the rate limit of service.
+func (l *rateLimiter) limit(service string) rateLimit {
+	if limit, ok := l.overrides[service]; ok {
+		return limit
+	}
+	return l.def
+}
+
+// allow returns whether a span of service can be sent at now.
+func (l *rateLimiter) allow(service string, now time.Time) bool {
+	limit := l.limit(service)
+	if limit.rate <= 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if now.Sub(l.lastPruned) >= pruneInterval {
+		l.prune(now)
+	}
+	b, ok := l.buckets[service]
+	if !ok {
+		b = &tokenBucket{tokens: limit.burst, last: now}
+		l.buckets[service] = b
+	}
+	b.refill(limit, now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune drops the buckets that have refilled by now. A full bucket is the
+// same as none. The caller must hold the lock.
+func (l *rateLimiter) prune(now time.Time) {
+	for service, b := range l.buckets {
+		limit := l.limit(service)
+		if b.refill(limit, now); b.tokens >= limit.burst {
+			delete(l.buckets, service)
+		}
+	}
+	l.reported = make(map[string]struct{})
+	l.lastPruned = now
+}
+
+// labels returns the service labels the rate limited spans of service are
+// reported with. The self metric label is service for the first
+// maxReportedServices services each pruneInterval. The Prometheus label,
+// which is never reset, is service only if it has its own limit, so its
+// values are bounded by the configuration.
+func (l *rateLimiter) labels(service string) (label, promLabel string) {
+	promLabel = otherServices
+	if _, ok := l.overrides[service]; ok {
+		promLabel = service
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, ok := l.reported[service]; !ok {
+		if len(l.reported) >= maxReportedServices {
+			return otherServices, promLabel
+		}
+		l.reported[service] = struct{}{}
+	}
+	return service, promLabel
+}
+
+// refill adds the tokens accumulated at limit's rate since the bucket was
+// last refilled, up to the burst.
+func (b *tokenBucket) refill(limit rateLimit, now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(limit.burst, b.tokens+elapsed.Seconds()*limit.rate)
+		b.last = now
+	}
+}
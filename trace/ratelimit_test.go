@@ -0,0 +1,183 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/tracespan"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(
+		&config.Params_SpanRateLimit{SpansPerSecond: 2},
+		map[string]*config.Params_SpanRateLimit{
+			"chatty": {SpansPerSecond: 1, Burst: 3},
+			"exempt": {},
+		},
+	)
+
+	now := time.Now()
+	allowed := func(service string, n int, at time.Time) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if l.allow(service, at) {
+				count++
+			}
+		}
+		return count
+	}
+
+	testCases := []struct {
+		service  string
+		at       time.Duration
+		expected int
+	}{
+		// The burst defaults to the rate.
+		{"default", 0, 2},
+		{"default", 500 * time.Millisecond, 1},
+		{"chatty", 0, 3},
+		{"chatty", time.Second, 1},
+		// Tokens do not accumulate beyond the burst.
+		{"chatty", time.Minute, 3},
+		{"exempt", 0, 10},
+	}
+	for _, tc := range testCases {
+		if n := allowed(tc.service, 10, now.Add(tc.at)); n != tc.expected {
+			t.Errorf("%s at %v: expected %d spans allowed, got %d", tc.service, tc.at, tc.expected, n)
+		}
+	}
+
+	// Buckets that have refilled are dropped, so the default bucket was
+	// dropped a minute in and the chatty one is a minute later.
+	if _, ok := l.buckets["chatty"]; !ok || len(l.buckets) != 1 {
+		t.Errorf("expected only the chatty bucket, got %v", l.buckets)
+	}
+	l.allow("default", now.Add(time.Minute+pruneInterval))
+	if _, ok := l.buckets["default"]; !ok || len(l.buckets) != 1 {
+		t.Errorf("expected only the default bucket, got %v", l.buckets)
+	}
+
+	for service, expected := range map[string]string{"chatty": "chatty", "exempt": "exempt", "reviews-v2-7bf8c9648f-7xkhs": "other"} {
+		if label, promLabel := l.labels(service); label != service || promLabel != expected {
+			t.Errorf("%s: expected labels %q and %q, got %q and %q", service, service, expected, label, promLabel)
+		}
+	}
+	// Only the first services each window are reported by name.
+	for i := len(l.reported); i < maxReportedServices; i++ {
+		l.labels(fmt.Sprintf("service-%d", i))
+	}
+	if label, _ := l.labels("late"); label != otherServices {
+		t.Errorf("expected label %q beyond %d services, got %q", otherServices, maxReportedServices, label)
+	}
+	if label, _ := l.labels("chatty"); label != "chatty" {
+		t.Errorf("expected label of a reported service kept, got %q", label)
+	}
+	l.allow("default", now.Add(time.Minute+2*pruneInterval))
+	if label, _ := l.labels("late"); label != "late" {
+		t.Errorf("expected label %q in a new window, got %q", "late", label)
+	}
+
+	if l := newRateLimiter(nil, map[string]*config.Params_SpanRateLimit{"exempt": {}}); l != nil {
+		t.Errorf("expected no rate limiter when no service is limited, got %#v", l)
+	}
+}
+
+func TestHandleTraceSpanRateLimit(t *testing.T) {
+	timestamp, err := types.TimestampProto(time.Now())
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+
+	var msgs []*tracespan.InstanceMsg
+	for i := 0; i < 3; i++ {
+		for _, client := range []bool{false, true} {
+			msgs = append(msgs, &tracespan.InstanceMsg{
+				SpanId:          fmt.Sprintf("%v-%d", client, i),
+				TraceId:         "trace",
+				StartTime:       &policy.TimeStamp{Value: timestamp},
+				EndTime:         &policy.TimeStamp{Value: timestamp},
+				SourceName:      "frontend",
+				DestinationName: "backend",
+				ClientSpan:      client,
+			})
+		}
+	}
+
+	recorder := &export.Recorder{}
+	h, err := BuildHandler(&config.Params{
+		ServiceSpanRateLimits: map[string]*config.Params_SpanRateLimit{
+			"frontend": {SpansPerSecond: 0.001, Burst: 1},
+		},
+	}, recorder, nil)
+	if err != nil {
+		t.Fatalf("failed to build handler: %v", err)
+	}
+	if err := h.HandleTraceSpan(context.Background(), msgs); err != nil {
+		t.Fatalf("HandleTraceSpan errored: %v", err)
+	}
+
+	// Client spans are attributed to the source service.
+	var ids []string
+	for _, s := range recorder.Spans() {
+		ids = append(ids, s.ID)
+	}
+	if expected := "false-0,true-0,false-1,false-2"; strings.Join(ids, ",") != expected {
+		t.Errorf("expected spans %s recorded, got %v", expected, ids)
+	}
+}
+
+func TestBuildHandlerRateLimit(t *testing.T) {
+	testCases := []struct {
+		name           string
+		params         *config.Params
+		expectedErrMsg string
+	}{
+		{"none", &config.Params{}, ""},
+		{"default", &config.Params{SpanRateLimit: &config.Params_SpanRateLimit{SpansPerSecond: 100, Burst: 200}}, ""},
+		{"negative rate", &config.Params{SpanRateLimit: &config.Params_SpanRateLimit{SpansPerSecond: -1}}, "spans_per_second"},
+		{
+			"negative override burst",
+			&config.Params{ServiceSpanRateLimits: map[string]*config.Params_SpanRateLimit{"a": {Burst: -1}}},
+			"burst",
+		},
+		{
+			"empty service",
+			&config.Params{ServiceSpanRateLimits: map[string]*config.Params_SpanRateLimit{"": {SpansPerSecond: 1}}},
+			"service name",
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := BuildHandler(tc.params, &export.Recorder{}, nil)
+		if tc.expectedErrMsg == "" {
+			if err != nil {
+				t.Errorf("%s: Did not expect error, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg) {
+			t.Errorf("%s: Expected error to contain '%s', got %v", tc.name, tc.expectedErrMsg, err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"container/list"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/selfmetric"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+// headSampler keeps a percentage of traces based on a hash of their trace
+// ID.
+type headSampler struct {
+	// threshold is the largest trace ID hash kept.
+	threshold uint64
+	all       bool
+}
+
+// newHeadSampler returns a headSampler keeping percentage of traces. A
+// percentage of zero, or 100 and above, keeps all traces.
+func newHeadSampler(percentage float64) headSampler {
+	if percentage <= 0 || percentage >= 100 {
+		return headSampler{all: true}
+	}
+	return headSampler{threshold: uint64(percentage / 100 * math.MaxUint64)}
+}
+
+// keep returns whether the trace traceID is kept.
+func (s headSampler) keep(traceID string) bool {
+	if s.all {
+		return true
+	}
+	h := fnv.New64a()
+	h.Write([]byte(traceID))
+	return mix(h.Sum64()) <= s.threshold
+}
+
+// mix spreads the bits of the hash x across all of its bits. FNV leaves
+// the high bits of IDs differing only in their last characters close
+// together.
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// bufferedTrace is a trace whose spans are buffered until it is decided
+// upon.
+type bufferedTrace struct {
+	id        string
+	first     time.Time
+	spans     []telemetry.Span
+	important bool
+}
+
+// decision is whether a trace was kept, remembered for the spans received
+// after it was decided upon.
+type decision struct {
+	keep    bool
+	expires time.Time
+}
+
+// tailSampler buffers the spans of each trace for a window and keeps the
+// traces with an error or slow span, and the head sampled ones.
+type tailSampler struct {
+	window    time.Duration
+	threshold time.Duration
+	maxTraces int
+	head      headSampler
+	record    func(telemetry.Span)
+
+	lock sync.Mutex
+	// order holds the buffered traces in the order their first span was
+	// received, which is also the order they are decided upon.
+	order     *list.List
+	traces    map[string]*list.Element
+	decisions map[string]decision
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newTailSampler(window, threshold time.Duration, maxTraces int, head headSampler, record func(telemetry.Span)) *tailSampler {
+	return &tailSampler{
+		window:    window,
+		threshold: threshold,
+		maxTraces: maxTraces,
+		head:      head,
+		record:    record,
+		order:     list.New(),
+		traces:    make(map[string]*list.Element),
+		decisions: make(map[string]decision),
+	}
+}
+
+// add buffers span received at now. A span of a request that isError or
+// exceeding the latency threshold makes its trace important.
+func (s *tailSampler) add(span telemetry.Span, isError bool, now time.Time) {
+	important := isError || (s.threshold > 0 && span.Duration > s.threshold)
+
+	s.lock.Lock()
+	if d, ok := s.decisions[span.TraceID]; ok {
+		s.lock.Unlock()
+		if d.keep {
+			s.record(span)
+		} else {
+			selfmetric.RecordSpansDropped("sampled", 1)
+		}
+		return
+	}
+
+	e, ok := s.traces[span.TraceID]
+	if !ok {
+		e = s.order.PushBack(&bufferedTrace{id: span.TraceID, first: now})
+		s.traces[span.TraceID] = e
+	}
+	t := e.Value.(*bufferedTrace)
+	t.spans = append(t.spans, span)
+	t.important = t.important || important
+
+	var decided []*bufferedTrace
+	if s.maxTraces > 0 && s.order.Len() > s.maxTraces {
+		decided = append(decided, s.remove(s.order.Front(), now))
+	}
+	s.lock.Unlock()
+
+	s.apply(decided)
+}
+
+// remove removes the buffered trace e and remembers its decision. The
+// caller must hold the lock.
+func (s *tailSampler) remove(e *list.Element, now time.Time) *bufferedTrace {
+	t := s.order.Remove(e).(*bufferedTrace)
+	delete(s.traces, t.id)
+	s.decisions[t.id] = decision{keep: s.keep(t), expires: now.Add(s.window)}
+	return t
+}
+
+// keep returns whether the buffered trace t is kept.
+func (s *tailSampler) keep(t *bufferedTrace) bool {
+	return t.important || s.head.keep(t.id)
+}
+
+// apply records the spans of the kept traces.
+func (s *tailSampler) apply(traces []*bufferedTrace) {
+	for _, t := range traces {
+		if !s.keep(t) {
+			selfmetric.RecordSpansDropped("sampled", len(t.spans))
+			continue
+		}
+		for _, span := range t.spans {
+			s.record(span)
+		}
+	}
+}
+
+// decide decides upon the traces buffered for the window before now and
+// forgets the expired decisions.
+func (s *tailSampler) decide(now time.Time) {
+	var decided []*bufferedTrace
+
+	s.lock.Lock()
+	cutoff := now.Add(-s.window)
+	for e := s.order.Front(); e != nil && !e.Value.(*bufferedTrace).first.After(cutoff); e = s.order.Front() {
+		decided = append(decided, s.remove(e, now))
+	}
+	for id, d := range s.decisions {
+		if !d.expires.After(now) {
+			delete(s.decisions, id)
+		}
+	}
+	s.lock.Unlock()
+
+	s.apply(decided)
+}
+
+// start decides upon the buffered traces every period until the
+// tailSampler is closed.
+func (s *tailSampler) start(period time.Duration) {
+	s.done = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C:
+				s.decide(t)
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// close stops deciding upon traces periodically and decides upon all the
+// buffered traces.
+func (s *tailSampler) close() {
+	if s.done != nil {
+		close(s.done)
+		s.wg.Wait()
+		s.done = nil
+	}
+
+	s.lock.Lock()
+	var decided []*bufferedTrace
+	now := time.Now()
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		decided = append(decided, s.remove(e, now))
+	}
+	s.lock.Unlock()
+
+	s.apply(decided)
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+func TestHeadSampler(t *testing.T) {
+	for _, p := range []float64{0, 100} {
+		s := newHeadSampler(p)
+		for i := 0; i < 100; i++ {
+			if !s.keep(fmt.Sprintf("trace-%d", i)) {
+				t.Fatalf("%v%%: expected all traces kept", p)
+			}
+		}
+	}
+
+	s := newHeadSampler(25)
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		id := fmt.Sprintf("%032x", i)
+		keep := s.keep(id)
+		if keep != s.keep(id) {
+			t.Fatalf("expected consistent decision for trace %s", id)
+		}
+		if keep {
+			kept++
+		}
+	}
+	if kept < 2250 || kept > 2750 {
+		t.Errorf("expected about 25%% of traces kept, got %d of 10000", kept)
+	}
+}
+
+// spanRecorder records the trace and span IDs of spans.
+type spanRecorder []string
+
+func (r *spanRecorder) record(s telemetry.Span) {
+	*r = append(*r, s.TraceID+"/"+s.ID)
+}
+
+func (r *spanRecorder) String() string {
+	ids := append([]string(nil), *r...)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestTailSampler(t *testing.T) {
+	var recorded spanRecorder
+	// A head sampler keeping no traces.
+	s := newTailSampler(10*time.Second, time.Second, 0, headSampler{}, recorded.record)
+
+	now := time.Now()
+	s.add(telemetry.Span{TraceID: "error", ID: "1"}, false, now)
+	s.add(telemetry.Span{TraceID: "error", ID: "2"}, true, now.Add(time.Second))
+	s.add(telemetry.Span{TraceID: "slow", ID: "1", Duration: 2 * time.Second}, false, now)
+	s.add(telemetry.Span{TraceID: "ok", ID: "1", Duration: time.Millisecond}, false, now)
+
+	s.decide(now.Add(5 * time.Second))
+	if len(recorded) != 0 {
+		t.Fatalf("expected spans buffered for the window, got %v", recorded.String())
+	}
+
+	s.decide(now.Add(10 * time.Second))
+	if expected := "error/1,error/2,slow/1"; recorded.String() != expected {
+		t.Errorf("expected %s recorded, got %s", expected, recorded.String())
+	}
+
+	// Late spans follow the decision made for their trace.
+	recorded = nil
+	s.add(telemetry.Span{TraceID: "error", ID: "3"}, false, now.Add(12*time.Second))
+	s.add(telemetry.Span{TraceID: "ok", ID: "2", Duration: 2 * time.Second}, false, now.Add(12*time.Second))
+	if expected := "error/3"; recorded.String() != expected {
+		t.Errorf("expected late %s recorded, got %s", expected, recorded.String())
+	}
+
+	// Once the decision expires the trace is buffered again.
+	recorded = nil
+	s.decide(now.Add(20 * time.Second))
+	s.add(telemetry.Span{TraceID: "ok", ID: "3", Duration: 2 * time.Second}, false, now.Add(21*time.Second))
+	s.close()
+	if expected := "ok/3"; recorded.String() != expected {
+		t.Errorf("expected %s recorded on close, got %s", expected, recorded.String())
+	}
+}
+
+func TestTailSamplerMaxTraces(t *testing.T) {
+	var recorded spanRecorder
+	s := newTailSampler(time.Minute, 0, 2, headSampler{}, recorded.record)
+	defer s.close()
+
+	now := time.Now()
+	s.add(telemetry.Span{TraceID: "a", ID: "1"}, true, now)
+	s.add(telemetry.Span{TraceID: "b", ID: "1"}, true, now)
+	if len(recorded) != 0 {
+		t.Fatalf("expected spans buffered, got %s", recorded.String())
+	}
+	s.add(telemetry.Span{TraceID: "c", ID: "1"}, true, now)
+	if expected := "a/1"; recorded.String() != expected {
+		t.Errorf("expected oldest trace decided early, got %s", recorded.String())
+	}
+}
+
+func TestBuildHandlerSampling(t *testing.T) {
+	testCases := []struct {
+		name           string
+		sampling       *config.Params_TraceSampling
+		expectedErrMsg string
+	}{
+		{"none", nil, ""},
+		{"head", &config.Params_TraceSampling{HeadPercentage: 10}, ""},
+		{"tail", &config.Params_TraceSampling{Tail: &config.Params_TraceSampling_TailSampling{Window: time.Second}}, ""},
+		{"negative percentage", &config.Params_TraceSampling{HeadPercentage: -1}, "head_percentage"},
+		{"percentage above 100", &config.Params_TraceSampling{HeadPercentage: 101}, "head_percentage"},
+		{"no window", &config.Params_TraceSampling{Tail: &config.Params_TraceSampling_TailSampling{}}, "window"},
+		{"short window", &config.Params_TraceSampling{Tail: &config.Params_TraceSampling_TailSampling{Window: 3 * time.Nanosecond}}, "must be >= 1s"},
+		{
+			"negative max traces",
+			&config.Params_TraceSampling{Tail: &config.Params_TraceSampling_TailSampling{Window: time.Second, MaxTraces: -1}},
+			"max_traces",
+		},
+	}
+
+	for _, tc := range testCases {
+		h, err := BuildHandler(&config.Params{TraceSampling: tc.sampling}, &export.Recorder{}, nil)
+		if tc.expectedErrMsg == "" {
+			if err != nil {
+				t.Errorf("%s: Did not expect error, got %v", tc.name, err)
+				continue
+			}
+			h.Close()
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg) {
+			t.Errorf("%s: Expected error to contain '%s', got %v", tc.name, tc.expectedErrMsg, err)
+		}
+	}
+}
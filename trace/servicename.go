@@ -0,0 +1,142 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"istio.io/istio/mixer/pkg/adapter"
+)
+
+// templatePart is either literal text or the name of an attribute whose
+// value is substituted.
+type templatePart struct {
+	literal string
+	attr    string
+}
+
+// nameTemplate builds a service name from span attributes.
+type nameTemplate []templatePart
+
+// parseNameTemplate parses s, literal text with attribute names in braces.
+func parseNameTemplate(s string) (nameTemplate, error) {
+	var t nameTemplate
+	for s != "" {
+		open := strings.IndexAny(s, "{}")
+		if open < 0 {
+			t = append(t, templatePart{literal: s})
+			break
+		}
+		if s[open] == '}' {
+			return nil, errors.New("unexpected '}'")
+		}
+		if open > 0 {
+			t = append(t, templatePart{literal: s[:open]})
+		}
+		s = s[open+1:]
+		end := strings.IndexAny(s, "{}")
+		if end < 0 || s[end] != '}' {
+			return nil, errors.New("unterminated '{'")
+		}
+		if end == 0 {
+			return nil, errors.New("empty attribute name")
+		}
+		t = append(t, templatePart{attr: s[:end]})
+		s = s[end+1:]
+	}
+	if len(t) == 0 {
+		return nil, errors.New("empty template")
+	}
+	return t, nil
+}
+
+// execute returns the service name built from attrs, or false if an
+// attribute of the template is missing or empty.
+func (t nameTemplate) execute(attrs map[string]interface{}) (string, bool) {
+	var b strings.Builder
+	for _, p := range t {
+		if p.attr == "" {
+			b.WriteString(p.literal)
+			continue
+		}
+		v, ok := attrs[p.attr]
+		if !ok || v == nil {
+			return "", false
+		}
+		s := fmt.Sprint(v)
+		if s == "" {
+			return "", false
+		}
+		b.WriteString(s)
+	}
+	return b.String(), true
+}
+
+// serviceNamer resolves the service name of spans from the first
+// applicable template for their kind.
+type serviceNamer struct {
+	server []nameTemplate
+	client []nameTemplate
+}
+
+// buildServiceNamer returns the serviceNamer configured by c, or nil if
+// spans keep their default service name.
+func buildServiceNamer(c *config.Params_ServiceName) (*serviceNamer, *adapter.ConfigErrors) {
+	var errs *adapter.ConfigErrors
+	parse := func(field string, templates []string) []nameTemplate {
+		var parsed []nameTemplate
+		for _, s := range templates {
+			t, err := parseNameTemplate(s)
+			if err != nil {
+				errs = errs.Appendf(field, "template %q is invalid: %v", s, err)
+				continue
+			}
+			parsed = append(parsed, t)
+		}
+		return parsed
+	}
+	n := &serviceNamer{
+		server: parse("ServiceName.server", c.GetServer()),
+		client: parse("ServiceName.client", c.GetClient()),
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	if len(n.server) == 0 && len(n.client) == 0 {
+		return nil, nil
+	}
+	return n, nil
+}
+
+// resolve returns the service name of a span with attrs, or def if no
+// template applies.
+func (n *serviceNamer) resolve(attrs map[string]interface{}, clientSpan bool, def string) string {
+	if n == nil {
+		return def
+	}
+	templates := n.server
+	if clientSpan {
+		templates = n.client
+	}
+	for _, t := range templates {
+		if name, ok := t.execute(attrs); ok {
+			return name
+		}
+	}
+	return def
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/tracespan"
+)
+
+func TestParseNameTemplate(t *testing.T) {
+	attrs := map[string]interface{}{
+		"destination.workload.name":      "reviews-v2",
+		"destination.workload.namespace": "default",
+		"destination.port":               int64(9080),
+		"empty":                          "",
+	}
+
+	testCases := []struct {
+		template       string
+		expected       string
+		expectedOK     bool
+		expectedErrMsg string
+	}{
+		{"{destination.workload.name}.{destination.workload.namespace}", "reviews-v2.default", true, ""},
+		{"svc-{destination.workload.name}:{destination.port}", "svc-reviews-v2:9080", true, ""},
+		{"reviews", "reviews", true, ""},
+		{"{destination.workload.name}.{missing}", "", false, ""},
+		{"{empty}", "", false, ""},
+		{"", "", false, "empty template"},
+		{"{}", "", false, "empty attribute name"},
+		{"{name", "", false, "unterminated"},
+		{"{a{b}}", "", false, "unterminated"},
+		{"name}", "", false, "unexpected"},
+	}
+
+	for _, tc := range testCases {
+		tmpl, err := parseNameTemplate(tc.template)
+		if tc.expectedErrMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErrMsg) {
+				t.Errorf("%q: Expected error to contain '%s', got %v", tc.template, tc.expectedErrMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: Did not expect error, got %v", tc.template, err)
+			continue
+		}
+		name, ok := tmpl.execute(attrs)
+		if name != tc.expected || ok != tc.expectedOK {
+			t.Errorf("%q: expected (%q, %v), got (%q, %v)", tc.template, tc.expected, tc.expectedOK, name, ok)
+		}
+	}
+}
+
+func TestHandleTraceSpanServiceName(t *testing.T) {
+	timestamp, err := types.TimestampProto(time.Now())
+	if err != nil {
+		t.Fatalf("error creating timestamp: %v", err)
+	}
+	msg := func(id string, client bool, tags map[string]string) *tracespan.InstanceMsg {
+		spanTags := make(map[string]*policy.Value, len(tags))
+		for k, v := range tags {
+			spanTags[k] = &policy.Value{Value: &policy.Value_StringValue{StringValue: v}}
+		}
+		return &tracespan.InstanceMsg{
+			SpanId:          id,
+			TraceId:         "trace",
+			StartTime:       &policy.TimeStamp{Value: timestamp},
+			EndTime:         &policy.TimeStamp{Value: timestamp},
+			SourceName:      "productpage-v1-6b746f74dc-9stvs",
+			DestinationName: "reviews-v2-7bf8c9648f-7xkhs",
+			ClientSpan:      client,
+			SpanTags:        spanTags,
+		}
+	}
+
+	recorder := &export.Recorder{}
+	h, err := BuildHandler(&config.Params{
+		ServiceName: &config.Params_ServiceName{
+			Server: []string{
+				"{destination.workload.name}.{destination.workload.namespace}",
+				"{destination.service.name}",
+			},
+		},
+	}, recorder, nil)
+	if err != nil {
+		t.Fatalf("failed to build handler: %v", err)
+	}
+	err = h.HandleTraceSpan(context.Background(), []*tracespan.InstanceMsg{
+		msg("workload", false, map[string]string{
+			"destination.workload.name":      "reviews-v2",
+			"destination.workload.namespace": "default",
+			"destination.service.name":       "reviews",
+		}),
+		msg("fallback", false, map[string]string{"destination.service.name": "reviews"}),
+		msg("default", false, nil),
+		msg("client", true, map[string]string{"destination.service.name": "reviews"}),
+	})
+	if err != nil {
+		t.Fatalf("HandleTraceSpan errored: %v", err)
+	}
+
+	expected := map[string]string{
+		"workload": "reviews-v2.default",
+		"fallback": "reviews",
+		"default":  "reviews-v2-7bf8c9648f-7xkhs",
+		"client":   "productpage-v1-6b746f74dc-9stvs",
+	}
+	spans := recorder.Spans()
+	if len(spans) != len(expected) {
+		t.Fatalf("expected %d spans, got %d", len(expected), len(spans))
+	}
+	for _, s := range spans {
+		if s.ServiceName != expected[s.ID] {
+			t.Errorf("%s: expected service name %q, got %q", s.ID, expected[s.ID], s.ServiceName)
+		}
+	}
+
+	_, err = BuildHandler(&config.Params{
+		ServiceName: &config.Params_ServiceName{Client: []string{"{source.workload.name"}},
+	}, recorder, nil)
+	if err == nil || !strings.Contains(err.Error(), "ServiceName.client") {
+		t.Errorf("Expected error to contain '%s', got %v", "ServiceName.client", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"time"
+
+	"github.com/newrelic/newrelic-telemetry-sdk-go/telemetry"
+)
+
+// MetricRecorder aggregates the metrics derived from spans.
+type MetricRecorder interface {
+	// RecordCount increases the count of the named metric with attrs by
+	// v.
+	RecordCount(name string, attrs map[string]interface{}, v float64)
+	// RecordSummary adds v to the summary of the named metric with attrs.
+	RecordSummary(name string, attrs map[string]interface{}, v float64)
+}
+
+// spanMetrics derives request rate, error and duration metrics from spans.
+type spanMetrics struct {
+	recorder MetricRecorder
+	requests string
+	errors   string
+	duration string
+}
+
+// newSpanMetrics returns spanMetrics recording metrics named under
+// namespace with recorder.
+func newSpanMetrics(namespace string, recorder MetricRecorder) *spanMetrics {
+	return &spanMetrics{
+		recorder: recorder,
+		requests: namespace + ".request.total",
+		errors:   namespace + ".request.errors",
+		duration: namespace + ".request.duration.milliseconds",
+	}
+}
+
+// record records the request span with statusCode, counting it as an
+// error if isError.
+func (m *spanMetrics) record(span telemetry.Span, statusCode int64, isError bool, clientSpan bool) {
+	reporter := "destination"
+	if clientSpan {
+		reporter = "source"
+	}
+	attrs := func() map[string]interface{} {
+		return map[string]interface{}{
+			"service.name":  span.ServiceName,
+			"span.name":     span.Name,
+			"response.code": statusCode,
+			"reporter":      reporter,
+		}
+	}
+
+	m.recorder.RecordCount(m.requests, attrs(), 1)
+	if isError {
+		m.recorder.RecordCount(m.errors, attrs(), 1)
+	}
+	m.recorder.RecordSummary(m.duration, attrs(), float64(span.Duration)/float64(time.Millisecond))
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 New Relic Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/newrelic/newrelic-istio-adapter/config"
+	"github.com/newrelic/newrelic-istio-adapter/export"
+	policy "istio.io/api/policy/v1beta1"
+	"istio.io/istio/mixer/template/tracespan"
+)
+
+// metricRecorder records the sum of the values recorded for each metric
+// and attribute set.
+type metricRecorder map[string]float64
+
+func (r metricRecorder) key(name string, attrs map[string]interface{}) string {
+	return fmt.Sprintf("%s %v/%v/%v/%v", name, attrs["service.name"], attrs["span.name"], attrs["response.code"], attrs["reporter"])
+}
+
+func (r metricRecorder) RecordCount(name string, attrs map[string]interface{}, v float64) {
+	r[r.key(name, attrs)] += v
+}
+
+func (r metricRecorder) RecordSummary(name string, attrs map[string]interface{}, v float64) {
+	r[r.key(name, attrs)] += v
+}
+
+func TestSpanMetrics(t *testing.T) {
+	start := time.Now()
+	msg := func(id string, code int64, d time.Duration, client bool) *tracespan.InstanceMsg {
+		startTime, err := types.TimestampProto(start)
+		if err != nil {
+			t.Fatalf("error creating timestamp: %v", err)
+		}
+		endTime, err := types.TimestampProto(start.Add(d))
+		if err != nil {
+			t.Fatalf("error creating timestamp: %v", err)
+		}
+		return &tracespan.InstanceMsg{
+			SpanId:          id,
+			TraceId:         "trace-" + id,
+			SpanName:        "GET /",
+			StartTime:       &policy.TimeStamp{Value: startTime},
+			EndTime:         &policy.TimeStamp{Value: endTime},
+			SourceName:      "frontend",
+			DestinationName: "backend",
+			HttpStatusCode:  code,
+			ClientSpan:      client,
+		}
+	}
+
+	metrics := metricRecorder{}
+	spans := &export.Recorder{}
+	// Metrics are derived from the spans sampled out too.
+	h, err := BuildHandler(&config.Params{
+		Namespace:     "istio",
+		SpanMetrics:   &config.Params_SpanMetrics{},
+		TraceSampling: &config.Params_TraceSampling{HeadPercentage: 1e-9},
+		ErrorSpans:    &config.Params_ErrorSpans{ClientErrors: true},
+	}, spans, metrics)
+	if err != nil {
+		t.Fatalf("failed to build handler: %v", err)
+	}
+	err = h.HandleTraceSpan(context.Background(), []*tracespan.InstanceMsg{
+		msg("1", 200, 10*time.Millisecond, false),
+		msg("2", 200, 30*time.Millisecond, false),
+		msg("3", 503, 5*time.Millisecond, false),
+		msg("4", 200, 40*time.Millisecond, true),
+		msg("5", 404, 20*time.Millisecond, false),
+	})
+	if err != nil {
+		t.Fatalf("HandleTraceSpan errored: %v", err)
+	}
+
+	expected := metricRecorder{
+		"istio.span.request.total backend/GET //200/destination":                 2,
+		"istio.span.request.duration.milliseconds backend/GET //200/destination": 40,
+		"istio.span.request.total backend/GET //503/destination":                 1,
+		"istio.span.request.errors backend/GET //503/destination":                1,
+		"istio.span.request.duration.milliseconds backend/GET //503/destination": 5,
+		"istio.span.request.total backend/GET //404/destination":                 1,
+		"istio.span.request.errors backend/GET //404/destination":                1,
+		"istio.span.request.duration.milliseconds backend/GET //404/destination": 20,
+		"istio.span.request.total frontend/GET //200/source":                     1,
+		"istio.span.request.duration.milliseconds frontend/GET //200/source":     40,
+	}
+	if !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("expected metrics %v, got %v", expected, metrics)
+	}
+	if n := len(spans.Spans()); n != 0 {
+		t.Errorf("expected all spans sampled out, got %d", n)
+	}
+}
+
+func TestSpanMetricsNamespace(t *testing.T) {
+	testCases := []struct {
+		params   *config.Params
+		expected string
+	}{
+		{&config.Params{}, "span"},
+		{&config.Params{Namespace: "istio"}, "istio.span"},
+		{&config.Params{Namespace: "istio", SpanMetrics: &config.Params_SpanMetrics{Namespace: "mesh"}}, "mesh"},
+	}
+	for _, tc := range testCases {
+		if ns := spanMetricsNamespace(tc.params); ns != tc.expected {
+			t.Errorf("%v: expected namespace %q, got %q", tc.params, tc.expected, ns)
+		}
+	}
+}